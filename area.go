@@ -0,0 +1,86 @@
+package svgpath
+
+// Direction of a subpath
+type Orientation int
+
+// In SVG coordinates y axis goes down, so clockwise on screen is the
+// direction from x axis to y axis
+const (
+	CounterClockwise Orientation = -1
+	Degenerate       Orientation = 0
+	Clockwise        Orientation = 1
+)
+
+// Signed area enclosed by the path with pending transforms applied, sum
+// of SubpathAreas. Subpaths drawn in opposite directions cancel each
+// other, the way holes are made with the `nonzero` fill rule.
+//
+func (sp *SvgPath) Area() float64 {
+	total := 0.0
+	for _, a := range sp.SubpathAreas() {
+		total += a
+	}
+	return total
+}
+
+// Signed area of each subpath, positive for clockwise ones. Open
+// subpaths are closed by a line, the way they are filled.
+//
+func (sp *SvgPath) SubpathAreas() []float64 {
+	if sp.err != nil {
+		return []float64{}
+	}
+
+	contours := sp.contours()
+	res := make([]float64, len(contours))
+	for i, c := range contours {
+		for j := range c.prims {
+			res[i] += c.prims[j].area()
+		}
+		if !c.closed && len(c.prims) > 0 {
+			x, y := c.prims[len(c.prims)-1].end()
+			res[i] += (x*c.startY - c.startX*y) / 2
+		}
+	}
+	return res
+}
+
+// Direction of each subpath, by the sign of its area
+//
+func (sp *SvgPath) Orientation() []Orientation {
+	areas := sp.SubpathAreas()
+	res := make([]Orientation, len(areas))
+	for i, a := range areas {
+		switch {
+		case a > 0:
+			res[i] = Clockwise
+		case a < 0:
+			res[i] = CounterClockwise
+		}
+	}
+	return res
+}
+
+// Integral of (x dy - y dx) / 2 along the primitive, by Green's
+// theorem. Summed over a closed contour it gives the enclosed area.
+//
+func (pr *primitive) area() float64 {
+	p := &pr.p
+	cross := func(i, j int) float64 {
+		return p[2*i]*p[2*j+1] - p[2*j]*p[2*i+1]
+	}
+
+	switch pr.kind {
+	case primQuad:
+		return (2*cross(0, 1) + 2*cross(1, 2) + cross(0, 2)) / 6
+	case primCubic:
+		return (6*cross(0, 1) + 3*cross(0, 2) + cross(0, 3) +
+			3*cross(1, 2) + 3*cross(1, 3) + 6*cross(2, 3)) / 20
+	case primArc:
+		// x = c + u cos(t) + v sin(t): the integrand is c × (dx, dy)
+		// plus the constant u × v
+		a := &pr.arc
+		return (a.cx*(p[3]-p[1]) - a.cy*(p[2]-p[0]) + (a.ux*a.vy-a.uy*a.vx)*a.dtheta) / 2
+	}
+	return cross(0, 1) / 2
+}
@@ -0,0 +1,66 @@
+package svgpath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// shoelace area of a fine polyline approximation
+func polylineArea(sp *SvgPath) float64 {
+	total := 0.0
+	for _, pl := range sp.Flatten(1e-4) {
+		points := pl.Points
+		for i := range points {
+			a, b := points[i], points[(i+1)%len(points)]
+			total += (a.X*b.Y - b.X*a.Y) / 2
+		}
+	}
+	return total
+}
+
+func TestAreaPolygons(t *testing.T) {
+	assert.Equal(t, 100.0, Parse("M0 0H10V10H0Z").Area(), "clockwise on screen")
+	assert.Equal(t, -100.0, Parse("M0 0V10H10V0Z").Area())
+	assert.Equal(t, 50.0, Parse("M0 0H10V10").Area(), "open subpath is closed")
+	assert.Equal(t, 0.0, Parse("M0 0H10").Area())
+	assert.Equal(t, 0.0, Parse("").Area())
+
+	// square with a hole
+	sp := Parse("M0 0H10V10H0ZM2 2V8H8V2Z")
+	assert.Equal(t, []float64{100, -36}, sp.SubpathAreas())
+	assert.Equal(t, 64.0, sp.Area())
+}
+
+func TestAreaCurves(t *testing.T) {
+	paths := []string{
+		"M0 0Q50 100 100 0Z",
+		"M0 0C0 100 100 100 100 0Z",
+		"M0 0C100 100 0 100 100 0",
+		"M0 0S50 100 100 0T200 0Z",
+		"M0 0A80 20 30 0 0 100 50Z",
+		"M0 0A80 20 30 1 1 100 50",
+		"M0 0R10 20 30 0 40 20Z",
+	}
+	for _, path := range paths {
+		sp := Parse(path)
+		assert.InDelta(t, polylineArea(sp), sp.Area(), 0.05, path)
+	}
+
+	// circle and parabola segment
+	assert.InDelta(t, -math.Pi*100, Parse("M10 0A10 10 0 1 0 -10 0A10 10 0 1 0 10 0Z").Area(), 1e-9)
+	assert.InDelta(t, -2.0/3*100*50, Parse("M0 0Q50 100 100 0Z").Area(), 1e-9)
+}
+
+func TestAreaTransformed(t *testing.T) {
+	assert.InDelta(t, 400, Parse("M0 0H10V10H0Z").Scale(2, 2).Area(), 1e-9)
+	assert.InDelta(t, -100, Parse("M0 0H10V10H0Z").Scale(-1, 1).Area(), 1e-9)
+	assert.InDelta(t, 4*math.Pi*100, Parse("M10 0A10 10 0 1 1 -10 0A10 10 0 1 1 10 0Z").Scale(2, 2).Area(), 1e-9)
+}
+
+func TestOrientation(t *testing.T) {
+	sp := Parse("M0 0H10V10H0ZM2 2V8H8V2ZM0 0H5")
+	assert.Equal(t, []Orientation{Clockwise, CounterClockwise, Degenerate}, sp.Orientation())
+	assert.Equal(t, []Orientation{}, Parse("M0 0X").Orientation())
+}
@@ -0,0 +1,124 @@
+package svgpath
+
+import (
+	"math"
+)
+
+// Axis-aligned rectangle
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+func (r Rect) Width() float64 {
+	return r.MaxX - r.MinX
+}
+
+func (r Rect) Height() float64 {
+	return r.MaxY - r.MinY
+}
+
+// bboxBuilder grows a rectangle point by point
+type bboxBuilder struct {
+	r     Rect
+	empty bool
+}
+
+func (b *bboxBuilder) add(x, y float64) {
+	if b.empty {
+		b.r = Rect{x, y, x, y}
+		b.empty = false
+		return
+	}
+	b.r.MinX = math.Min(b.r.MinX, x)
+	b.r.MinY = math.Min(b.r.MinY, y)
+	b.r.MaxX = math.Max(b.r.MaxX, x)
+	b.r.MaxY = math.Max(b.r.MaxY, y)
+}
+
+// Append roots in (0, 1) of Bézier derivative given by its coefficients:
+// d0*(1-t)^2 + 2*d1*(1-t)*t + d2*t^2
+//
+func bezierExtrema(d0, d1, d2 float64, roots []float64) []float64 {
+	a := d0 - 2*d1 + d2
+	b := 2 * (d1 - d0)
+	c := d0
+
+	if math.Abs(a) < epsilon {
+		// linear
+		if b != 0 {
+			if t := -c / b; t > 0 && t < 1 {
+				roots = append(roots, t)
+			}
+		}
+		return roots
+	}
+
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return roots
+	}
+	sq := math.Sqrt(disc)
+	for _, t := range []float64{(-b + sq) / (2 * a), (-b - sq) / (2 * a)} {
+		if t > 0 && t < 1 {
+			roots = append(roots, t)
+		}
+	}
+	return roots
+}
+
+// Add primitive extents: end points and inner extrema
+//
+func (b *bboxBuilder) primitive(pr *primitive) {
+	b.add(pr.start())
+	b.add(pr.end())
+
+	var ts [4]float64
+	roots := ts[:0]
+	p := &pr.p
+
+	switch pr.kind {
+	case primQuad:
+		for axis := 0; axis < 2; axis++ {
+			d0, d1 := p[2+axis]-p[axis], p[4+axis]-p[2+axis]
+			roots = bezierExtrema(d0, (d0+d1)/2, d1, roots)
+		}
+	case primCubic:
+		for axis := 0; axis < 2; axis++ {
+			roots = bezierExtrema(p[2+axis]-p[axis], p[4+axis]-p[2+axis], p[6+axis]-p[4+axis], roots)
+		}
+	case primArc:
+		// x'(t) = -ux*sin(t) + vx*cos(t) = 0 at atan2(vx, ux) and opposite,
+		// same for y
+		a := &pr.arc
+		for _, t0 := range []float64{math.Atan2(a.vx, a.ux), math.Atan2(a.vy, a.uy)} {
+			for _, t := range []float64{t0, t0 + math.Pi} {
+				if a.param(t) <= 1 {
+					b.add(a.at(t))
+				}
+			}
+		}
+	}
+
+	for _, t := range roots {
+		b.add(pr.at(t))
+	}
+}
+
+// Tight bounding box of the path, including extrema of curves and arcs.
+// Pending transforms are taken into account, without applying them to
+// the path. Empty path (or a path with an error) gives a zero Rect.
+//
+func (sp *SvgPath) BBox() Rect {
+	if sp.err != nil {
+		return Rect{}
+	}
+
+	b := bboxBuilder{empty: true}
+	for _, c := range sp.contours() {
+		b.add(c.startX, c.startY)
+		for i := range c.prims {
+			b.primitive(&c.prims[i])
+		}
+	}
+	return b.r
+}
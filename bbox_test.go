@@ -0,0 +1,68 @@
+package svgpath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertRect(t *testing.T, expected, actual Rect, msg string) {
+	const delta = 1e-9
+	assert.InDelta(t, expected.MinX, actual.MinX, delta, msg)
+	assert.InDelta(t, expected.MinY, actual.MinY, delta, msg)
+	assert.InDelta(t, expected.MaxX, actual.MaxX, delta, msg)
+	assert.InDelta(t, expected.MaxY, actual.MaxY, delta, msg)
+}
+
+func TestBBoxLines(t *testing.T) {
+	assertRect(t, Rect{0, 0, 10, 20}, Parse("M0 0L10 5V20H3z").BBox(), "lines")
+	assertRect(t, Rect{5, 5, 5, 5}, Parse("M5 5").BBox(), "single point")
+	assert.Equal(t, Rect{}, Parse("").BBox(), "empty")
+	assert.Equal(t, Rect{}, Parse("M0 0").Matrix([]float64{1}).BBox(), "error")
+}
+
+func TestBBoxCurves(t *testing.T) {
+	// control points are out of the curve
+	assertRect(t, Rect{0, 0, 10, 5}, Parse("M0 0Q5 10 10 0").BBox(), "quadratic")
+	assertRect(t, Rect{0, 0, 10, 7.5}, Parse("M0 0C0 10 10 10 10 0").BBox(), "cubic")
+	assertRect(t, Rect{0, -7.5, 20, 7.5}, Parse("M0 0C0 10 10 10 10 0S20 -10 20 0").BBox(), "smooth cubic")
+	assertRect(t, Rect{0, -5, 20, 5}, Parse("M0 0Q5 10 10 0T20 0").BBox(), "smooth quadratic")
+
+	// cubic with both extrema on one axis, compare with sampling
+	b := Parse("M0 0C40 5 -30 5 10 0").BBox()
+	assert.True(t, b.MaxX > 10 && b.MinX < 0)
+	minX, maxX := 0.0, 0.0
+	for i := 0; i <= 100000; i++ {
+		u := float64(i) / 100000
+		x := 3*(1-u)*(1-u)*u*40 - 3*(1-u)*u*u*30 + u*u*u*10
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+	}
+	assert.InDelta(t, minX, b.MinX, 1e-6)
+	assert.InDelta(t, maxX, b.MaxX, 1e-6)
+}
+
+func TestBBoxArcs(t *testing.T) {
+	assertRect(t, Rect{0, -5, 10, 0}, Parse("M0 0A5 5 0 0 1 10 0").BBox(), "upper half circle")
+	assertRect(t, Rect{0, 0, 10, 5}, Parse("M0 0A5 5 0 0 0 10 0").BBox(), "lower half circle")
+	assertRect(t, Rect{-10, -10, 10, 10}, Parse("M10 0A10 10 0 1 1 -10 0A10 10 0 1 1 10 0").BBox(), "circle")
+	assertRect(t, Rect{0, -1, 4, 1}, Parse("M0 0A2 1 0 1 1 4 0A2 1 0 1 1 0 0").BBox(), "ellipse")
+
+	// too small radii are scaled up
+	assertRect(t, Rect{0, -5, 10, 0}, Parse("M0 0A1 1 0 0 1 10 0").BBox(), "scaled radii")
+
+	// rotated ellipse: rx=2, ry=1 at 45 degrees, half-width is sqrt((4+1)/2)
+	h := math.Sqrt(2.5)
+	assertRect(t, Rect{-h, -h, h, h},
+		Parse("M-1.4142135623730951 -1.4142135623730951A2 1 45 1 1 1.4142135623730951 1.4142135623730951A2 1 45 1 1 -1.4142135623730951 -1.4142135623730951").BBox(), "rotated")
+}
+
+func TestBBoxTransformed(t *testing.T) {
+	sp := Parse("M0 0A5 5 0 0 1 10 0").Scale(2, 1).Rotate(90, 0, 0)
+	assertRect(t, Rect{0, 0, 5, 20}, sp.BBox(), "pending stack")
+	assert.Equal(t, 2, len(sp.stack), "stack is not applied")
+	assertRect(t, sp.BBox(), sp.Clone().Abs().BBox(), "same as after applying")
+
+	sp = Parse("M0 0C0 10 10 10 10 0").SkewX(45)
+	assertRect(t, sp.Clone().Abs().Unshort().BBox(), sp.BBox(), "skewed cubic")
+}
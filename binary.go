@@ -0,0 +1,386 @@
+package svgpath
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Binary format:
+//
+//    version      byte (1)
+//    precision    byte, digits after the decimal point
+//    count        uvarint, number of segments
+//    segments     opcode byte [, uvarint points count for `R`], params
+//
+// Opcode is the command index in binaryCommands, with bit 0x10 set for
+// relative commands. Arc flags are stored in opcode bits 0x20 (large arc)
+// and 0x40 (sweep).
+//
+// Params are quantized to `precision` digits (rotation of arcs to
+// `precision+2`, same as Round) and written as zig-zag varints. Absolute
+// coordinates are written as deltas from the current point, so they are
+// as small as relative ones.
+
+const binaryVersion = 1
+
+// Precision used by MarshalBinary
+const DefaultBinaryPrecision = 3
+
+const binaryCommands = "MLHVCSQTARZ"
+
+const (
+	opRelative = 0x10
+	opLargeArc = 0x20
+	opSweep    = 0x40
+)
+
+// max quantized value, floats are exact integers up to it
+const binaryMaxValue = 1 << 53
+
+// Implements encoding.BinaryMarshaler with DefaultBinaryPrecision.
+//
+func (sp *SvgPath) MarshalBinary() ([]byte, error) {
+	return sp.MarshalBinaryPrecision(DefaultBinaryPrecision)
+}
+
+// Encode path with coordinates rounded to precision digits (0..15), the
+// decoded path is the same as after Round(precision). Pending transforms
+// are applied to a copy, sp is not modified.
+//
+func (sp *SvgPath) MarshalBinaryPrecision(precision int) ([]byte, error) {
+	if sp.err != nil {
+		return nil, sp.err
+	}
+	if precision < 0 || precision > 15 {
+		return nil, errors.Errorf("SvgPath: binary precision %d out of range 0..15", precision)
+	}
+
+	// Round keeps relative coordinates from drifting, after it
+	// quantization is exact
+	sp = sp.Clone().Round(precision)
+
+	e := binaryEncoder{
+		buf:   make([]byte, 0, 2+binary.MaxVarintLen64+len(sp.segments)*8),
+		scale: math.Pow10(precision),
+	}
+	e.buf = append(e.buf, binaryVersion, byte(precision))
+	e.uvarint(uint64(len(sp.segments)))
+
+	for i, s := range sp.segments {
+		if err := e.segment(s); err != nil {
+			return nil, errors.Wrapf(err, "SvgPath: can't encode segment %d", i)
+		}
+	}
+	return e.buf, nil
+}
+
+type binaryEncoder struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+	scale   float64
+
+	// quantized current point and subpath start
+	x, y           int64
+	startX, startY int64
+}
+
+func (e *binaryEncoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *binaryEncoder) varint(v int64) {
+	n := binary.PutVarint(e.scratch[:], v) // zig-zag
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func quantize(v, scale float64) (int64, error) {
+	q := math.Round(v * scale)
+	if math.IsNaN(q) || math.Abs(q) > binaryMaxValue {
+		return 0, errors.Errorf("param %g out of range", v)
+	}
+	return int64(q), nil
+}
+
+// Write coordinate v, return its quantized absolute value
+//
+func (e *binaryEncoder) coord(v float64, base int64, relative bool) (int64, error) {
+	q, err := quantize(v, e.scale)
+	if err != nil {
+		return 0, err
+	}
+	if relative {
+		e.varint(q)
+		q += base
+		if q > binaryMaxValue || q < -binaryMaxValue {
+			return 0, errors.Errorf("point %g out of range", v)
+		}
+		return q, nil
+	}
+	e.varint(q - base)
+	return q, nil
+}
+
+func (e *binaryEncoder) segment(s *Segment) error {
+	c := s.Cmd()
+	op := strings.IndexByte(binaryCommands, byte(c.Abs()))
+	if c == 0 || op < 0 {
+		return errors.Errorf("bad command %q", s.Command)
+	}
+
+	p := s.Params
+	if c.Abs() == CmdCatmullRomTo {
+		if len(p) < c.ParamCount() || len(p)%2 != 0 {
+			return errors.Errorf("bad params count %d for command %s", len(p), c)
+		}
+	} else if len(p) != c.ParamCount() {
+		return errors.Errorf("bad params count %d for command %s", len(p), c)
+	}
+
+	rel := c.IsRelative()
+	if rel {
+		op |= opRelative
+	}
+	if c.Abs() == CmdArcTo {
+		if p[3] != 0 {
+			op |= opLargeArc
+		}
+		if p[4] != 0 {
+			op |= opSweep
+		}
+	}
+	e.buf = append(e.buf, byte(op))
+
+	var err error
+	switch c.Abs() {
+	case CmdClosePath:
+		e.x, e.y = e.startX, e.startY
+		return nil
+
+	case CmdHLineTo:
+		e.x, err = e.coord(p[0], e.x, rel)
+		return err
+
+	case CmdVLineTo:
+		e.y, err = e.coord(p[0], e.y, rel)
+		return err
+
+	case CmdArcTo:
+		// rx, ry, rotation with better precision; flags are in opcode
+		for i, scale := range []float64{e.scale, e.scale, e.scale * 100} {
+			q, err := quantize(p[i], scale)
+			if err != nil {
+				return err
+			}
+			e.varint(q)
+		}
+		p = p[5:]
+
+	case CmdCatmullRomTo:
+		e.uvarint(uint64(len(p) / 2))
+	}
+
+	// points; for relative commands all of them are relative to the
+	// start of the segment
+	baseX, baseY := e.x, e.y
+	for i := 0; i < len(p); i += 2 {
+		if !rel {
+			baseX, baseY = e.x, e.y
+		}
+		if e.x, err = e.coord(p[i], baseX, rel); err != nil {
+			return err
+		}
+		if e.y, err = e.coord(p[i+1], baseY, rel); err != nil {
+			return err
+		}
+	}
+
+	if c.Abs() == CmdMoveTo {
+		e.startX, e.startY = e.x, e.y
+	}
+	return nil
+}
+
+// Implements encoding.BinaryUnmarshaler. Replaces the path, dropping
+// pending transforms and the chain error.
+//
+func (sp *SvgPath) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("SvgPath: truncated binary data")
+	}
+	if data[0] != binaryVersion {
+		return errors.Errorf("SvgPath: unsupported binary version %d", data[0])
+	}
+	if data[1] > 15 {
+		return errors.Errorf("SvgPath: binary precision %d out of range 0..15", data[1])
+	}
+
+	d := binaryDecoder{data: data, pos: 2, scale: math.Pow10(int(data[1]))}
+	count := d.uvarint()
+	// every segment takes at least one byte
+	if d.err == nil && count > uint64(len(data)-d.pos) {
+		d.err = errors.New("SvgPath: truncated binary data")
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	segments := make([]*Segment, count)
+	slab := make([]Segment, count)
+	for i := range segments {
+		s := &slab[i]
+		d.segment(s)
+		if d.err != nil {
+			return errors.Wrapf(d.err, "SvgPath: can't decode segment %d", i)
+		}
+		if i == 0 && s.Command != "M" && s.Command != "m" {
+			return errors.New("SvgPath: string should start with `M` or `m`")
+		}
+		segments[i] = s
+	}
+	if d.pos != len(data) {
+		return errors.New("SvgPath: trailing bytes in binary data")
+	}
+
+	*sp = SvgPath{segments: segments, stack: []*Matrix{}}
+	return nil
+}
+
+type binaryDecoder struct {
+	data  []byte
+	pos   int
+	err   error
+	scale float64
+
+	x, y           int64
+	startX, startY int64
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		d.err = errors.New("truncated binary data")
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		d.err = errors.New("truncated binary data")
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+// Read coordinate, return its value and quantized absolute value
+//
+func (d *binaryDecoder) coord(base int64, relative bool) (float64, int64) {
+	// deltas of absolute coordinates take up to twice the range
+	q := d.varint()
+	if q > 2*binaryMaxValue || q < -2*binaryMaxValue {
+		d.err = errors.New("param out of range")
+		return 0, 0
+	}
+
+	// base is in range too, so the sum can't overflow
+	abs := base + q
+	if abs > binaryMaxValue || abs < -binaryMaxValue {
+		d.err = errors.New("point out of range")
+		return 0, 0
+	}
+	if relative {
+		return float64(q) / d.scale, abs
+	}
+	return float64(abs) / d.scale, abs
+}
+
+func (d *binaryDecoder) segment(s *Segment) {
+	if d.pos >= len(d.data) {
+		d.err = errors.New("truncated binary data")
+		return
+	}
+	op := d.data[d.pos]
+	d.pos++
+
+	index := int(op & 0x0F)
+	if index >= len(binaryCommands) || op&0x80 != 0 {
+		d.err = errors.Errorf("bad opcode %#x", op)
+		return
+	}
+	c := Command(binaryCommands[index])
+	if c != CmdArcTo && op&(opLargeArc|opSweep) != 0 {
+		d.err = errors.Errorf("bad opcode %#x", op)
+		return
+	}
+	rel := op&opRelative != 0
+	if rel {
+		c = c.Rel()
+	}
+	s.Command = c.String()
+
+	n := c.ParamCount()
+	if c.Abs() == CmdCatmullRomTo {
+		points := d.uvarint()
+		if points < 2 || points > uint64(len(d.data)-d.pos) {
+			if d.err == nil {
+				d.err = errors.Errorf("bad points count %d", points)
+			}
+			return
+		}
+		n = int(points) * 2
+	}
+	p := make([]float64, n)
+	s.Params = p
+
+	switch c.Abs() {
+	case CmdClosePath:
+		d.x, d.y = d.startX, d.startY
+		return
+
+	case CmdHLineTo:
+		p[0], d.x = d.coord(d.x, rel)
+		return
+
+	case CmdVLineTo:
+		p[0], d.y = d.coord(d.y, rel)
+		return
+
+	case CmdArcTo:
+		p[0] = float64(d.varint()) / d.scale
+		p[1] = float64(d.varint()) / d.scale
+		p[2] = float64(d.varint()) / (d.scale * 100)
+		if op&opLargeArc != 0 {
+			p[3] = 1
+		}
+		if op&opSweep != 0 {
+			p[4] = 1
+		}
+		p = p[5:]
+	}
+
+	baseX, baseY := d.x, d.y
+	for i := 0; i < len(p); i += 2 {
+		if !rel {
+			baseX, baseY = d.x, d.y
+		}
+		p[i], d.x = d.coord(baseX, rel)
+		p[i+1], d.y = d.coord(baseY, rel)
+	}
+
+	if c.Abs() == CmdMoveTo {
+		d.startX, d.startY = d.x, d.y
+	}
+}
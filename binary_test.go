@@ -0,0 +1,102 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	paths := []string{
+		"M10 20L30.5 40.25H100V-7C1 2 3 4 5 6S1 2 3 4Q1 2 3 4T5 6A10 20 30.5 1 0 40 50Z",
+		"m10 20l30.5 40.25h100v-7c1 2 3 4 5 6s1 2 3 4q1 2 3 4t5 6a10 20 30.5 0 1 40 50zm1 1",
+		"M0 0R1 2 3 4 5 6r1 1 2 2",
+		"M1e6 -1e6l0.001 -0.001",
+	}
+	for _, path := range paths {
+		sp, err := NewSvgPath(path)
+		assert.Nil(t, err)
+
+		data, err := sp.MarshalBinary()
+		assert.Nil(t, err)
+
+		back := &SvgPath{}
+		assert.Nil(t, back.UnmarshalBinary(data), path)
+		assert.Equal(t, sp.ToString(), back.ToString())
+	}
+}
+
+func TestBinaryPrecision(t *testing.T) {
+	sp := Parse("M1.23456 2.34567l1.11111 1.11111L3.33333 3.33333A1.5 1.5 12.34567 0 1 7.77777 8.88888")
+
+	data, err := sp.MarshalBinaryPrecision(2)
+	assert.Nil(t, err)
+
+	back := &SvgPath{}
+	assert.Nil(t, back.UnmarshalBinary(data))
+	assert.Equal(t, Parse(sp.ToString()).Round(2).ToString(), back.ToString(), "same as Round")
+
+	_, err = sp.MarshalBinaryPrecision(16)
+	assert.NotNil(t, err)
+	_, err = Parse("M1e300 0").MarshalBinaryPrecision(2)
+	assert.NotNil(t, err)
+}
+
+func TestBinaryTransformed(t *testing.T) {
+	sp := Parse("M10 10L20 20").Scale(2, 2)
+	data, err := sp.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sp.stack), "source is not modified")
+
+	back := &SvgPath{}
+	assert.Nil(t, back.UnmarshalBinary(data))
+	assert.Equal(t, "M20 20L40 40", back.ToString())
+}
+
+func TestBinaryCompact(t *testing.T) {
+	sp := Parse("M100.5 200.5L110.5 210.5L120.5 220.5L130.5 230.5")
+	data, err := sp.MarshalBinaryPrecision(1)
+	assert.Nil(t, err)
+	assert.True(t, len(data) < len(sp.ToString())/2, "%d bytes", len(data))
+}
+
+func TestBinaryBroken(t *testing.T) {
+	data, err := Parse("M10 20L30 40A1 1 0 1 1 5 5").MarshalBinary()
+	assert.Nil(t, err)
+
+	back := &SvgPath{}
+	for i := 0; i < len(data); i++ {
+		assert.NotNil(t, back.UnmarshalBinary(data[:i]), "truncated at %d", i)
+	}
+	assert.NotNil(t, back.UnmarshalBinary(append(append([]byte{}, data...), 0)), "trailing bytes")
+
+	bad := append([]byte{}, data...)
+	bad[0] = 2
+	assert.NotNil(t, back.UnmarshalBinary(bad), "version")
+
+	bad = append([]byte{}, data...)
+	bad[3] = 0x0F
+	assert.NotNil(t, back.UnmarshalBinary(bad), "opcode")
+
+	bad = append([]byte{}, data...)
+	bad[3] = 1
+	assert.NotNil(t, back.UnmarshalBinary(bad), "first command")
+}
+
+func BenchmarkBinaryDecode(b *testing.B) {
+	data, _ := Parse(benchPath).MarshalBinary()
+	sp := &SvgPath{}
+	for i := 0; i < b.N; i++ {
+		_ = sp.UnmarshalBinary(data)
+	}
+}
+
+func BenchmarkBinaryParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = PathParse(benchPath)
+	}
+}
+
+const benchPath = "M228.4 39.6c-11.3-11.2-29.6-11.2-40.9 0L100 127.1 12.5 39.6C1.2 28.4-17.1 28.4-28.4 39.6" +
+	"s-11.3 29.6 0 40.9l108 108c11.3 11.3 29.6 11.3 40.9 0l108-108c11.3-11.3 11.3-29.6 0-40.9z" +
+	"M50 50a25 25 0 1 0 50 0 25 25 0 1 0-50 0zQ10 20 30 40T50 60H70V80"
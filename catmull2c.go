@@ -0,0 +1,37 @@
+package svgpath
+
+// Convert an open Catmull-Rom spline through points [x0, y0, x1, y1, ...]
+// to cubic bézier curves. End tangents are computed by repeating the
+// first and the last points, the same way Raphael does.
+//
+// Return [[x1, y1, x2, y2, x, y], ...] for each span
+//
+func catmullRom2c(points []float64) [][]float64 {
+	result := [][]float64{}
+	n := len(points)
+
+	for i := 0; i+2 < n; i += 2 {
+		// p0 .. p3 are [x, y] pairs around the span p1 -> p2
+		p0 := i - 2
+		if i == 0 {
+			p0 = i
+		}
+		p1 := i
+		p2 := i + 2
+		p3 := i + 4
+		if p3 >= n {
+			p3 = p2
+		}
+
+		result = append(result, []float64{
+			(-points[p0] + 6*points[p1] + points[p2]) / 6,
+			(-points[p0+1] + 6*points[p1+1] + points[p2+1]) / 6,
+			(points[p1] + 6*points[p2] - points[p3]) / 6,
+			(points[p1+1] + 6*points[p2+1] - points[p3+1]) / 6,
+			points[p2],
+			points[p2+1],
+		})
+	}
+
+	return result
+}
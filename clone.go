@@ -0,0 +1,51 @@
+package svgpath
+
+// Deep copy of the path, including pending transforms and the sticky
+// error. The copy shares no memory with the original.
+//
+func (sp *SvgPath) Clone() *SvgPath {
+	segments := make([]*Segment, len(sp.segments))
+	for i, s := range sp.segments {
+		segments[i] = s.Clone()
+	}
+
+	stack := make([]*Matrix, len(sp.stack))
+	for i, m := range sp.stack {
+		stack[i] = m.Clone()
+	}
+
+	return &SvgPath{
+		segments: segments,
+		stack:    stack,
+		err:      sp.err,
+	}
+}
+
+func (s *Segment) Clone() *Segment {
+	return &Segment{Command: s.Command, Params: append([]float64{}, s.Params...)}
+}
+
+func (mx *Matrix) Clone() *Matrix {
+	queue := make([][]float64, len(mx.queue))
+	for i, m := range mx.queue {
+		queue[i] = append([]float64{}, m...)
+	}
+
+	var cache []float64
+	if mx.cache != nil {
+		cache = append([]float64{}, mx.cache...)
+	}
+
+	return &Matrix{queue: queue, cache: cache}
+}
+
+// Path string of sp with pending transforms applied, without
+// modifying sp. Unlike the ToString method, it can be called on a path
+// shared between goroutines.
+//
+func ToString(sp *SvgPath) string {
+	if len(sp.stack) == 0 {
+		return sp.ToString()
+	}
+	return sp.Clone().ToString()
+}
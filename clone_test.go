@@ -0,0 +1,46 @@
+package svgpath
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	sp, err := NewSvgPath("M10.4 10.4 l 20 20 A5 5 0 0 1 40 40")
+	assert.Nil(t, err)
+	sp.Translate(10, 0)
+
+	clone := sp.Clone()
+	clone.Scale(2, 2).Round(0)
+	assert.Equal(t, "M41 21l40 40A10 10 0 0 1 100 80", clone.ToString())
+	assert.Equal(t, "M20.4 10.4l20 20A5 5 0 0 1 50 40", sp.ToString(), "should not change the original")
+
+	sp.Segments()[0].Params[0] = 0
+	assert.Equal(t, 41.0, clone.Segments()[0].Params[0], "should not share segments")
+}
+
+func TestToStringNonMutating(t *testing.T) {
+	sp, err := NewSvgPath("M10.4 10.4 l 20 20")
+	assert.Nil(t, err)
+	sp.Translate(10, 0)
+
+	assert.Equal(t, "M20.4 10.4l20 20", ToString(sp))
+	assert.Equal(t, 1, len(sp.stack), "should keep pending transforms")
+
+	var wg sync.WaitGroup
+	results := make([]string, 8)
+	for i := 0; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = ToString(sp)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		assert.Equal(t, "M20.4 10.4l20 20", r)
+	}
+}
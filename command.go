@@ -0,0 +1,258 @@
+package svgpath
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Command is a path command letter. Lower case letters are relative
+// commands, upper case letters are absolute ones.
+type Command byte
+
+const (
+	CmdMoveTo        Command = 'M'
+	CmdLineTo        Command = 'L'
+	CmdHLineTo       Command = 'H'
+	CmdVLineTo       Command = 'V'
+	CmdCubicTo       Command = 'C'
+	CmdSmoothCubicTo Command = 'S'
+	CmdQuadTo        Command = 'Q'
+	CmdSmoothQuadTo  Command = 'T'
+	CmdArcTo         Command = 'A'
+	CmdCatmullRomTo  Command = 'R'
+	CmdClosePath     Command = 'Z'
+)
+
+// Check if c is one of the known path commands
+//
+func (c Command) Valid() bool {
+	return isCommand(rune(c))
+}
+
+func (c Command) IsRelative() bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// Absolute form of the command
+//
+func (c Command) Abs() Command {
+	return c &^ 0x20
+}
+
+// Relative form of the command
+//
+func (c Command) Rel() Command {
+	return c | 0x20
+}
+
+// Number of params the command takes. For `R` it is the minimal count,
+// any number of extra points may follow.
+//
+func (c Command) ParamCount() int {
+	switch c.Abs() {
+	case CmdArcTo:
+		return 7
+	case CmdCubicTo:
+		return 6
+	case CmdQuadTo, CmdSmoothCubicTo, CmdCatmullRomTo:
+		return 4
+	case CmdMoveTo, CmdLineTo, CmdSmoothQuadTo:
+		return 2
+	case CmdHLineTo, CmdVLineTo:
+		return 1
+	}
+	return 0
+}
+
+func (c Command) String() string {
+	return string(rune(c))
+}
+
+// Typed command of the segment. Unknown command names give 0.
+//
+func (s *Segment) Cmd() Command {
+	if len(s.Command) != 1 || !Command(s.Command[0]).Valid() {
+		return 0
+	}
+	return Command(s.Command[0])
+}
+
+// PathSegment is a typed view of a Segment. Every Segment with valid
+// params converts to a PathSegment and back without loss.
+type PathSegment interface {
+	Cmd() Command
+	ToSegment() *Segment
+}
+
+type MoveTo struct {
+	X, Y     float64
+	Relative bool
+}
+
+type LineTo struct {
+	X, Y     float64
+	Relative bool
+}
+
+type HLineTo struct {
+	X        float64
+	Relative bool
+}
+
+type VLineTo struct {
+	Y        float64
+	Relative bool
+}
+
+type CubicTo struct {
+	X1, Y1, X2, Y2, X, Y float64
+	Relative             bool
+}
+
+// Cubic curve with the first control point reflected from the previous one
+type SmoothCubicTo struct {
+	X2, Y2, X, Y float64
+	Relative     bool
+}
+
+type QuadTo struct {
+	X1, Y1, X, Y float64
+	Relative     bool
+}
+
+// Quadratic curve with the control point reflected from the previous one
+type SmoothQuadTo struct {
+	X, Y     float64
+	Relative bool
+}
+
+type ArcTo struct {
+	Rx, Ry, Rotation float64
+	LargeArc, Sweep  bool
+	X, Y             float64
+	Relative         bool
+}
+
+// Catmull-Rom spline through points [x1, y1, x2, y2, ...]
+type CatmullRomTo struct {
+	Points   []float64
+	Relative bool
+}
+
+type ClosePath struct {
+	Relative bool
+}
+
+// pick absolute or relative form of the command
+func cmdFor(c Command, relative bool) Command {
+	if relative {
+		return c.Rel()
+	}
+	return c
+}
+
+func flag(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (s MoveTo) Cmd() Command        { return cmdFor(CmdMoveTo, s.Relative) }
+func (s LineTo) Cmd() Command        { return cmdFor(CmdLineTo, s.Relative) }
+func (s HLineTo) Cmd() Command       { return cmdFor(CmdHLineTo, s.Relative) }
+func (s VLineTo) Cmd() Command       { return cmdFor(CmdVLineTo, s.Relative) }
+func (s CubicTo) Cmd() Command       { return cmdFor(CmdCubicTo, s.Relative) }
+func (s SmoothCubicTo) Cmd() Command { return cmdFor(CmdSmoothCubicTo, s.Relative) }
+func (s QuadTo) Cmd() Command        { return cmdFor(CmdQuadTo, s.Relative) }
+func (s SmoothQuadTo) Cmd() Command  { return cmdFor(CmdSmoothQuadTo, s.Relative) }
+func (s ArcTo) Cmd() Command         { return cmdFor(CmdArcTo, s.Relative) }
+func (s CatmullRomTo) Cmd() Command  { return cmdFor(CmdCatmullRomTo, s.Relative) }
+func (s ClosePath) Cmd() Command     { return cmdFor(CmdClosePath, s.Relative) }
+
+func (s MoveTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.X, s.Y}}
+}
+
+func (s LineTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.X, s.Y}}
+}
+
+func (s HLineTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.X}}
+}
+
+func (s VLineTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.Y}}
+}
+
+func (s CubicTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.X1, s.Y1, s.X2, s.Y2, s.X, s.Y}}
+}
+
+func (s SmoothCubicTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.X2, s.Y2, s.X, s.Y}}
+}
+
+func (s QuadTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.X1, s.Y1, s.X, s.Y}}
+}
+
+func (s SmoothQuadTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.X, s.Y}}
+}
+
+func (s ArcTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{s.Rx, s.Ry, s.Rotation, flag(s.LargeArc), flag(s.Sweep), s.X, s.Y}}
+}
+
+func (s CatmullRomTo) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: append([]float64{}, s.Points...)}
+}
+
+func (s ClosePath) ToSegment() *Segment {
+	return &Segment{Command: s.Cmd().String(), Params: []float64{}}
+}
+
+// Convert segment to its typed form. Fails on unknown commands and
+// on params count not matching the command.
+//
+func (s *Segment) Typed() (PathSegment, error) {
+	c := s.Cmd()
+	if c == 0 {
+		return nil, errors.Errorf("SvgPath: bad command %q", s.Command)
+	}
+
+	p := s.Params
+	if c.Abs() == CmdCatmullRomTo {
+		if len(p) < c.ParamCount() || len(p)%2 != 0 {
+			return nil, errors.Errorf("SvgPath: bad params count %d for command %s", len(p), c)
+		}
+	} else if len(p) != c.ParamCount() {
+		return nil, errors.Errorf("SvgPath: bad params count %d for command %s", len(p), c)
+	}
+
+	rel := c.IsRelative()
+	switch c.Abs() {
+	case CmdMoveTo:
+		return MoveTo{X: p[0], Y: p[1], Relative: rel}, nil
+	case CmdLineTo:
+		return LineTo{X: p[0], Y: p[1], Relative: rel}, nil
+	case CmdHLineTo:
+		return HLineTo{X: p[0], Relative: rel}, nil
+	case CmdVLineTo:
+		return VLineTo{Y: p[0], Relative: rel}, nil
+	case CmdCubicTo:
+		return CubicTo{X1: p[0], Y1: p[1], X2: p[2], Y2: p[3], X: p[4], Y: p[5], Relative: rel}, nil
+	case CmdSmoothCubicTo:
+		return SmoothCubicTo{X2: p[0], Y2: p[1], X: p[2], Y: p[3], Relative: rel}, nil
+	case CmdQuadTo:
+		return QuadTo{X1: p[0], Y1: p[1], X: p[2], Y: p[3], Relative: rel}, nil
+	case CmdSmoothQuadTo:
+		return SmoothQuadTo{X: p[0], Y: p[1], Relative: rel}, nil
+	case CmdArcTo:
+		return ArcTo{Rx: p[0], Ry: p[1], Rotation: p[2], LargeArc: p[3] != 0, Sweep: p[4] != 0, X: p[5], Y: p[6], Relative: rel}, nil
+	case CmdCatmullRomTo:
+		return CatmullRomTo{Points: append([]float64{}, p...), Relative: rel}, nil
+	}
+	return ClosePath{Relative: rel}, nil
+}
@@ -0,0 +1,63 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand(t *testing.T) {
+	assert.True(t, Command('a').Valid())
+	assert.False(t, Command('x').Valid())
+	assert.True(t, Command('c').IsRelative())
+	assert.False(t, CmdCubicTo.IsRelative())
+	assert.Equal(t, CmdArcTo, Command('a').Abs())
+	assert.Equal(t, Command('h'), CmdHLineTo.Rel())
+	assert.Equal(t, 7, Command('a').ParamCount())
+	assert.Equal(t, 0, CmdClosePath.ParamCount())
+	assert.Equal(t, "S", CmdSmoothCubicTo.String())
+
+	assert.Equal(t, CmdQuadTo, (&Segment{Command: "Q"}).Cmd())
+	assert.Equal(t, Command(0), (&Segment{Command: "QQ"}).Cmd())
+}
+
+func TestTypedSegments(t *testing.T) {
+	segments, err := PathParse("M1 2l3 4H5v6C1 2 3 4 5 6s1 2 3 4Q1 2 3 4t5 6a1 2 3 0 1 4 5R1 2 3 4 5 6z")
+	assert.Nil(t, err)
+
+	typed := []PathSegment{}
+	for _, s := range segments {
+		ts, err := s.Typed()
+		assert.Nil(t, err)
+		typed = append(typed, ts)
+	}
+
+	assert.Equal(t, []PathSegment{
+		MoveTo{X: 1, Y: 2},
+		LineTo{X: 3, Y: 4, Relative: true},
+		HLineTo{X: 5},
+		VLineTo{Y: 6, Relative: true},
+		CubicTo{X1: 1, Y1: 2, X2: 3, Y2: 4, X: 5, Y: 6},
+		SmoothCubicTo{X2: 1, Y2: 2, X: 3, Y: 4, Relative: true},
+		QuadTo{X1: 1, Y1: 2, X: 3, Y: 4},
+		SmoothQuadTo{X: 5, Y: 6, Relative: true},
+		ArcTo{Rx: 1, Ry: 2, Rotation: 3, Sweep: true, X: 4, Y: 5, Relative: true},
+		CatmullRomTo{Points: []float64{1, 2, 3, 4, 5, 6}},
+		ClosePath{Relative: true},
+	}, typed)
+
+	for i, ts := range typed {
+		assert.Equal(t, segments[i], ts.ToSegment(), "should convert back without loss")
+	}
+}
+
+func TestTypedSegmentErrors(t *testing.T) {
+	_, err := (&Segment{Command: "X", Params: []float64{}}).Typed()
+	assert.Equal(t, `SvgPath: bad command "X"`, err.Error())
+
+	_, err = (&Segment{Command: "L", Params: []float64{1}}).Typed()
+	assert.Equal(t, "SvgPath: bad params count 1 for command L", err.Error())
+
+	_, err = (&Segment{Command: "r", Params: []float64{1, 2, 3, 4, 5}}).Typed()
+	assert.Equal(t, "SvgPath: bad params count 5 for command r", err.Error())
+}
@@ -0,0 +1,160 @@
+package svgpath
+
+import (
+	"math"
+	"sort"
+)
+
+// Rule deciding which points are inside a path, as `fill-rule` in SVG
+type FillRule int
+
+const (
+	NonZero FillRule = iota
+	EvenOdd
+)
+
+// Check if point is inside the path filled with the rule. Pending
+// transforms are applied, open subpaths are closed by a line.
+//
+func (sp *SvgPath) Contains(x, y float64, rule FillRule) bool {
+	w := sp.WindingNumber(x, y)
+	if rule == EvenOdd {
+		return w%2 != 0
+	}
+	return w != 0
+}
+
+// Winding number of the path around the point: crossings of the ray
+// from the point to +x, counted positive where the path goes towards +y.
+// Clockwise subpaths wind +1 around their inner points, as their areas
+// are positive. Curves and arcs are split into pieces monotone in y,
+// where the crossing is the only root of y(t) = y.
+//
+func (sp *SvgPath) WindingNumber(x, y float64) int {
+	if sp.err != nil {
+		return 0
+	}
+
+	w := 0
+	for _, c := range sp.contours() {
+		for i := range c.prims {
+			w += c.prims[i].winding(x, y)
+		}
+		if !c.closed && len(c.prims) > 0 {
+			x1, y1 := c.prims[len(c.prims)-1].end()
+			closing := primitive{kind: primLine, p: [8]float64{x1, y1, c.startX, c.startY}}
+			w += closing.winding(x, y)
+		}
+	}
+	return w
+}
+
+// Crossings of the ray from (x, y) to +x with the primitive
+//
+func (pr *primitive) winding(x, y float64) int {
+	p := &pr.p
+
+	// quick rejection by control points, or by the ellipse for arcs
+	var minY, maxY, maxX float64
+	switch pr.kind {
+	case primArc:
+		a := &pr.arc
+		ry, rx := math.Hypot(a.uy, a.vy), math.Hypot(a.ux, a.vx)
+		minY, maxY, maxX = a.cy-ry, a.cy+ry, a.cx+rx
+	default:
+		n := 2
+		switch pr.kind {
+		case primQuad:
+			n = 3
+		case primCubic:
+			n = 4
+		}
+		minY, maxY, maxX = p[1], p[1], p[0]
+		for i := 1; i < n; i++ {
+			minY = math.Min(minY, p[2*i+1])
+			maxY = math.Max(maxY, p[2*i+1])
+			maxX = math.Max(maxX, p[2*i])
+		}
+	}
+	if y < minY || y > maxY || x >= maxX {
+		return 0
+	}
+
+	if pr.kind == primLine {
+		return lineWinding(x, y, p[0], p[1], p[2], p[3])
+	}
+
+	// split at extrema of y(t)
+	var buf [6]float64
+	ts := append(buf[:0], 0)
+	switch pr.kind {
+	case primQuad:
+		d0, d1 := p[3]-p[1], p[5]-p[3]
+		ts = bezierExtrema(d0, (d0+d1)/2, d1, ts)
+	case primCubic:
+		ts = bezierExtrema(p[3]-p[1], p[5]-p[3], p[7]-p[5], ts)
+	case primArc:
+		// y'(t) = -uy*sin(t) + vy*cos(t) = 0 at atan2(vy, uy) and opposite
+		a := &pr.arc
+		t0 := math.Atan2(a.vy, a.uy)
+		for _, angle := range []float64{t0, t0 + math.Pi} {
+			// strictly inside, the ends are already there
+			if t := a.param(angle); t > 0 && t < 1 {
+				ts = append(ts, t)
+			}
+		}
+	}
+	sort.Float64s(ts)
+	ts = append(ts, 1)
+
+	w := 0
+	_, ya := pr.start()
+	for i := 0; i+1 < len(ts); i++ {
+		ta, tb := ts[i], ts[i+1]
+		var yb float64
+		if tb == 1 {
+			_, yb = pr.end()
+		} else {
+			_, yb = pr.at(tb)
+		}
+
+		// half-open ranges, so that shared ends are counted once
+		dir := 0
+		if ya <= y && y < yb {
+			dir = 1
+		} else if yb <= y && y < ya {
+			dir = -1
+		}
+		if dir != 0 {
+			lo, hi := ta, tb
+			for iter := 0; iter < 60 && lo < hi; iter++ {
+				mid := (lo + hi) / 2
+				if _, ym := pr.at(mid); (ym-y)*float64(dir) <= 0 {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			if xt, _ := pr.at((lo + hi) / 2); xt > x {
+				w += dir
+			}
+		}
+		ya = yb
+	}
+	return w
+}
+
+func lineWinding(x, y, x0, y0, x1, y1 float64) int {
+	dir := 0
+	if y0 <= y && y < y1 {
+		dir = 1
+	} else if y1 <= y && y < y0 {
+		dir = -1
+	} else {
+		return 0
+	}
+	if x0+(y-y0)*(x1-x0)/(y1-y0) > x {
+		return dir
+	}
+	return 0
+}
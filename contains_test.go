@@ -0,0 +1,78 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindingNumber(t *testing.T) {
+	sp := Parse("M0 0H10V10H0Z")
+	assert.Equal(t, 1, sp.WindingNumber(5, 5), "clockwise")
+	assert.Equal(t, 0, sp.WindingNumber(15, 5))
+	assert.Equal(t, 0, sp.WindingNumber(-5, 5))
+	assert.Equal(t, -1, Parse("M0 0V10H10V0Z").WindingNumber(5, 5))
+
+	// ray through vertices is counted once
+	diamond := Parse("M5 0L10 5 5 10 0 5Z")
+	assert.Equal(t, 1, diamond.WindingNumber(2, 5))
+	assert.Equal(t, 0, diamond.WindingNumber(-2, 5))
+	assert.Equal(t, 0, diamond.WindingNumber(-2, 0))
+
+	// open subpath is closed
+	assert.Equal(t, 1, Parse("M0 0H10V10").WindingNumber(8, 5))
+
+	assert.Equal(t, 0, Parse("").WindingNumber(0, 0))
+	assert.Equal(t, 0, Parse("M0 0X").WindingNumber(0, 0))
+}
+
+func TestContainsFillRules(t *testing.T) {
+	// same direction: nonzero fills the inner square, evenodd does not
+	sp := Parse("M0 0H10V10H0ZM2 2H8V8H2Z")
+	assert.Equal(t, 2, sp.WindingNumber(5, 5))
+	assert.True(t, sp.Contains(5, 5, NonZero))
+	assert.False(t, sp.Contains(5, 5, EvenOdd))
+	assert.True(t, sp.Contains(1, 5, EvenOdd))
+
+	// hole drawn in the opposite direction
+	sp = Parse("M0 0H10V10H0ZM2 2V8H8V2Z")
+	assert.False(t, sp.Contains(5, 5, NonZero))
+	assert.True(t, sp.Contains(1, 1, NonZero))
+	assert.False(t, sp.Contains(11, 5, NonZero))
+}
+
+func TestContainsCurves(t *testing.T) {
+	circle := Parse("M10 0A10 10 0 1 1-10 0A10 10 0 1 1 10 0Z")
+	assert.True(t, circle.Contains(0, 0, NonZero))
+	assert.True(t, circle.Contains(7, 7, NonZero))
+	assert.False(t, circle.Contains(7.2, 7.2, NonZero))
+	assert.True(t, circle.Contains(-9.9, 0, NonZero))
+	assert.True(t, circle.Contains(0, 9.9, NonZero))
+	assert.False(t, circle.Contains(0, 10.1, NonZero))
+
+	// curves against their fine polygons on a grid
+	paths := []string{
+		"M0 0C0 100 100 100 100 0C100-50 50 50 0 0Z",
+		"M0 0Q100 100 100 0T50 50Z",
+		"M0 0A60 20 30 1 1 100 50C50 100 0 50 50 25Z",
+		"M0 0C150 100-50 100 100 0Z",
+	}
+	for _, path := range paths {
+		sp := Parse(path)
+		polygon := sp.FlattenPath(1e-3)
+		for x := -30.0; x <= 130; x += 7.3 {
+			for y := -60.0; y <= 110; y += 6.1 {
+				assert.Equal(t, polygon.WindingNumber(x, y), sp.WindingNumber(x, y), "%s at %g, %g", path, x, y)
+			}
+		}
+	}
+}
+
+func TestContainsTransformed(t *testing.T) {
+	sp := Parse("M0 0H10V10H0Z").Translate(100, 0)
+	assert.True(t, sp.Contains(105, 5, NonZero))
+	assert.False(t, sp.Contains(5, 5, NonZero))
+
+	// mirrored path winds the other way
+	assert.Equal(t, -1, Parse("M0 0H10V10H0Z").Scale(-1, 1).WindingNumber(-5, 5))
+}
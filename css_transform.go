@@ -0,0 +1,291 @@
+package svgpath
+
+import (
+	"math"
+	"strings"
+)
+
+// Kind of CSS transform function argument
+type cssArg int
+
+const (
+	cssNumber cssArg = iota
+	cssLength
+	cssAngle
+)
+
+type cssFunction struct {
+	arg   cssArg
+	arity []int
+}
+
+// CSS transform functions with a 2D meaning. Names are lower case,
+// CSS function names are case-insensitive.
+var cssTransforms = map[string]cssFunction{
+	"matrix":     {cssNumber, []int{6}},
+	"matrix3d":   {cssNumber, []int{16}},
+	"translate":  {cssLength, []int{1, 2}},
+	"translatex": {cssLength, []int{1}},
+	"translatey": {cssLength, []int{1}},
+	"scale":      {cssNumber, []int{1, 2}},
+	"scalex":     {cssNumber, []int{1}},
+	"scaley":     {cssNumber, []int{1}},
+	"rotate":     {cssAngle, []int{1}},
+	"skew":       {cssAngle, []int{1, 2}},
+	"skewx":      {cssAngle, []int{1}},
+	"skewy":      {cssAngle, []int{1}},
+}
+
+// Angle units, converted to degrees
+var cssAngleUnits = map[string]float64{
+	"":     1, // SVG style unitless degrees
+	"deg":  1,
+	"rad":  180 / math.Pi,
+	"grad": 0.9,
+	"turn": 360,
+}
+
+func (ts *transformScanner) scanUnit() string {
+	start := ts.pos
+	for ts.more() && (isLetter(ts.str[ts.pos]) || ts.str[ts.pos] == '%') {
+		ts.pos++
+	}
+	return strings.ToLower(ts.str[start:ts.pos])
+}
+
+// Scan a number with the unit allowed for kind and convert it
+// to px, degrees or plain number
+//
+func (ts *transformScanner) scanCSSArg(name string, n int, kind cssArg) (float64, error) {
+	start := ts.pos
+	value, ok := ts.scanNumber()
+	if !ok {
+		return 0, ts.fail(name, n, "invalid argument %d of %s", n, name)
+	}
+
+	unitPos := ts.pos
+	unit := ts.scanUnit()
+	switch kind {
+	case cssLength:
+		// only absolute lengths make sense without a viewport
+		if unit == "" || unit == "px" {
+			return value, nil
+		}
+	case cssAngle:
+		if k, ok := cssAngleUnits[unit]; ok {
+			return value * k, nil
+		}
+	case cssNumber:
+		if unit == "" {
+			return value, nil
+		}
+		if unit == "%" && (name == "scale" || name == "scalex" || name == "scaley") {
+			return value / 100, nil
+		}
+	}
+
+	ts.pos = unitPos
+	err := ts.fail(name, n, "invalid unit `%s` in argument %d of %s", unit, n, name)
+	ts.pos = start
+	return 0, err
+}
+
+// Scan `name(args)` of CSS syntax
+//
+func (ts *transformScanner) scanCSSTransform(params []float64) (string, []float64, error) {
+	namePos := ts.pos
+	name := strings.ToLower(ts.scanName())
+	if name == "" {
+		return "", nil, ts.fail("", 0, "expected transform function")
+	}
+	fn, ok := cssTransforms[name]
+	if !ok {
+		ts.pos = namePos
+		return "", nil, ts.fail("", 0, "unknown transform function `%s`", ts.str[namePos:namePos+len(name)])
+	}
+
+	// no space allowed before `(` in CSS
+	if !ts.more() || ts.str[ts.pos] != '(' {
+		return "", nil, ts.fail(name, 0, "expected `(` after %s", name)
+	}
+	ts.pos++
+	ts.skipSpaces()
+
+	for ts.more() && ts.str[ts.pos] != ')' {
+		if len(params) > 0 {
+			if !ts.skipCommaSpaces() {
+				return "", nil, ts.fail(name, len(params)+1, "invalid argument %d of %s", len(params)+1, name)
+			}
+		}
+		value, err := ts.scanCSSArg(name, len(params)+1, fn.arg)
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, value)
+		ts.skipSpaces()
+	}
+
+	if !ts.more() {
+		return "", nil, ts.fail(name, 0, "expected `)` after arguments of %s", name)
+	}
+	if err := ts.checkArity(name, fn.arity, len(params)); err != nil {
+		return "", nil, err
+	}
+	ts.pos++
+
+	return name, params, nil
+}
+
+// Parse `transform-origin`: two values, x then y, each a length or a
+// `left`/`top` keyword. Two keywords may go in any order (`top left`),
+// otherwise the first value is x and the second is y, so `top 10px` and
+// `10px left` are invalid as in CSS. `right`, `bottom`, `center`,
+// percentages and single values (the other axis is centered) are
+// relative to the element box and are rejected, there is no box for a
+// bare path.
+//
+func parseCSSOrigin(origin string) (float64, float64, error) {
+	ts := &transformScanner{str: origin, units: true}
+
+	// keyword ("" for lengths), value and offset of each value
+	var keywords [2]string
+	var values [2]float64
+	var starts [2]int
+	count := 0
+
+	ts.skipSpaces()
+	if !ts.more() {
+		return 0, 0, nil
+	}
+	for ts.more() {
+		if count == 2 {
+			return 0, 0, ts.fail("transform-origin", 0, "transform-origin takes 2 values")
+		}
+		count++
+
+		start := ts.pos
+		keyword := strings.ToLower(ts.scanName())
+		switch keyword {
+		case "left", "top":
+		case "right", "bottom", "center":
+			ts.pos = start
+			return 0, 0, ts.fail("transform-origin", count, "`%s` in transform-origin needs an element box", keyword)
+		case "":
+			value, err := ts.scanCSSArg("transform-origin", count, cssLength)
+			if err != nil {
+				return 0, 0, err
+			}
+			values[count-1] = value
+		default:
+			ts.pos = start
+			return 0, 0, ts.fail("transform-origin", count, "invalid argument %d of transform-origin", count)
+		}
+		keywords[count-1], starts[count-1] = keyword, start
+		ts.skipSpaces()
+	}
+
+	if count == 1 {
+		return 0, 0, ts.fail("transform-origin", 0, "transform-origin with 1 value needs an element box to center the other axis")
+	}
+
+	if keywords[0] != "" && keywords[1] != "" {
+		if keywords[0] == keywords[1] {
+			ts.pos = starts[1]
+			return 0, 0, ts.fail("transform-origin", 2, "duplicate axis in argument 2 of transform-origin")
+		}
+		return 0, 0, nil
+	}
+	if keywords[0] == "top" {
+		ts.pos = starts[0]
+		return 0, 0, ts.fail("transform-origin", 1, "expected horizontal position in argument 1 of transform-origin")
+	}
+	if keywords[1] == "left" {
+		ts.pos = starts[1]
+		return 0, 0, ts.fail("transform-origin", 2, "expected vertical position in argument 2 of transform-origin")
+	}
+	return values[0], values[1], nil
+}
+
+// Parse CSS `transform` property value, with an optional (may be empty)
+// `transform-origin`. Angles accept deg, rad, grad and turn units
+// (degrees by default), lengths accept px or no unit. `matrix3d` is
+// collapsed to its 2D part. Unitless non-zero angles and lengths are
+// invalid in CSS, here they are accepted as in the `transform`
+// attribute of SVG.
+//
+func TransformParseCSS(transform, origin string) (*Matrix, error) {
+	matrix := NewMatrix()
+
+	ox, oy, err := parseCSSOrigin(origin)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(strings.TrimSpace(transform)) == "none" {
+		return matrix, nil
+	}
+
+	ts := &transformScanner{str: transform, units: true}
+	params := make([]float64, 0, 16)
+	ts.skipSpaces()
+
+	matrix.Translate(ox, oy)
+
+	for ts.more() {
+		cmd, args, err := ts.scanCSSTransform(params[:0])
+		if err != nil {
+			return nil, err
+		}
+		params = args
+
+		switch cmd {
+		case "matrix":
+			matrix.Matrix(append([]float64{}, params...))
+		case "matrix3d":
+			// column-major 4x4, keep x/y rows and columns
+			matrix.Matrix([]float64{params[0], params[1], params[4], params[5], params[12], params[13]})
+		case "translate":
+			if len(params) == 1 {
+				matrix.Translate(params[0], 0)
+			} else {
+				matrix.Translate(params[0], params[1])
+			}
+		case "translatex":
+			matrix.Translate(params[0], 0)
+		case "translatey":
+			matrix.Translate(0, params[0])
+		case "scale":
+			if len(params) == 1 {
+				matrix.Scale(params[0], params[0])
+			} else {
+				matrix.Scale(params[0], params[1])
+			}
+		case "scalex":
+			matrix.Scale(params[0], 1)
+		case "scaley":
+			matrix.Scale(1, params[0])
+		case "rotate":
+			matrix.Rotate(params[0], 0, 0)
+		case "skew":
+			ay := 0.0
+			if len(params) == 2 {
+				ay = params[1]
+			}
+			matrix.Matrix([]float64{1, math.Tan(ay * torad), math.Tan(params[0] * torad), 1, 0, 0})
+		case "skewx":
+			matrix.SkewX(params[0])
+		case "skewy":
+			matrix.SkewY(params[0])
+		}
+
+		// CSS separates functions with whitespace only
+		ts.skipSpaces()
+		if ts.more() && ts.str[ts.pos] == ',' {
+			return nil, ts.fail("", 0, "expected transform function")
+		}
+	}
+
+	matrix.Translate(-ox, -oy)
+
+	return matrix, nil
+}
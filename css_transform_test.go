@@ -0,0 +1,116 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformCSS(t *testing.T) {
+	d, err := Parse("M10 10L15 10").TransformCSS("rotate(0.25turn)", "10px 10px").Round(0).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M10 10L10 15", d, "turn units and origin")
+
+	d, err = Parse("M10 10L15 10").TransformCSS("rotate(1.5707963267948966rad)", "10 10").Round(0).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M10 10L10 15", d, "radians")
+
+	d, err = Parse("M10 10L15 10").TransformCSS("rotate(100grad)", "left 10px").Round(0).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 20L0 25", d, "gradians and keywords")
+
+	d, err = Parse("M0 0L10 10").TransformCSS("translateX(10px) translateY(-5px) scale(1.5)", "").Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M10-5L25 10", d, "translate and scale")
+
+	d, err = Parse("M0 0L10 10").TransformCSS("scaleX(200%) scaleY(3)", "").Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0L20 30", d, "scaleX and scaleY")
+
+	d, err = Parse("M5 5L15 20").TransformCSS("skew(75.96deg, 0)", "").Round(0).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M25 5L95 20", d, "skew")
+
+	d, err = Parse("M5 5L15 20").TransformCSS("SKEW(0deg,75.96deg)", "").Round(0).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M5 25L15 80", d, "skew y, case-insensitive")
+
+	d, err = Parse("M5 5 C20 30 10 15 30 15").TransformCSS("matrix3d(1.5, 0.5, 0, 0, 0.5, 1.5, 0, 0, 0, 0, 1, 0, 10, 15, 0, 1)", "").Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M20 25C55 70 32.5 42.5 62.5 52.5", d, "matrix3d")
+
+	d, err = Parse("M5 5L15 20").TransformCSS("none", "10px 10px").Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M5 5L15 20", d, "none")
+}
+
+func TestTransformCSSOrigin(t *testing.T) {
+	origins := map[string][2]float64{
+		"":          {0, 0},
+		"10px 20px": {10, 20},
+		"left 20px": {0, 20},
+		"10px top":  {10, 0},
+		"left top":  {0, 0},
+		"top left":  {0, 0},
+		"20 10":     {20, 10},
+		"LEFT 20":   {0, 20},
+	}
+	for origin, expected := range origins {
+		x, y, err := parseCSSOrigin(origin)
+		assert.Nil(t, err, origin)
+		assert.Equal(t, expected, [2]float64{x, y}, origin)
+	}
+}
+
+func TestTransformCSSErrors(t *testing.T) {
+	var te *TransformError
+
+	_, err := TransformParseCSS("translate(10em)", "")
+	assert.Equal(t, "SvgPath: invalid unit `em` in argument 1 of translate (at pos 12)", err.Error())
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, "translate", te.Func)
+	assert.Equal(t, 1, te.Arg)
+
+	_, err = TransformParseCSS("rotate(10deg, 1, 1)", "")
+	assert.Equal(t, "SvgPath: rotate takes 1 arguments, got 3 (at pos 18)", err.Error())
+
+	_, err = TransformParseCSS("rotate (10deg)", "")
+	assert.Equal(t, "SvgPath: expected `(` after rotate (at pos 6)", err.Error())
+
+	_, err = TransformParseCSS("rotate(10deg), scale(2)", "")
+	assert.Equal(t, "SvgPath: expected transform function (at pos 13)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "center")
+	assert.Equal(t, "SvgPath: `center` in transform-origin needs an element box (at pos 0)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "10px right")
+	assert.Equal(t, "SvgPath: `right` in transform-origin needs an element box (at pos 5)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "10px")
+	assert.Equal(t, "SvgPath: transform-origin with 1 value needs an element box to center the other axis (at pos 4)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "left left")
+	assert.Equal(t, "SvgPath: duplicate axis in argument 2 of transform-origin (at pos 5)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "top 10px")
+	assert.Equal(t, "SvgPath: expected horizontal position in argument 1 of transform-origin (at pos 0)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "20 LEFT")
+	assert.Equal(t, "SvgPath: expected vertical position in argument 2 of transform-origin (at pos 3)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "top top")
+	assert.Equal(t, "SvgPath: duplicate axis in argument 2 of transform-origin (at pos 4)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "50% 50%")
+	assert.Error(t, err, "percentages")
+
+	_, err = TransformParseCSS("scale(2)", "foo 1px")
+	assert.Equal(t, "SvgPath: invalid argument 1 of transform-origin (at pos 0)", err.Error())
+
+	_, err = TransformParseCSS("scale(2)", "1px 2px 3px")
+	assert.Equal(t, "SvgPath: transform-origin takes 2 values (at pos 8)", err.Error())
+
+	sp := Parse("M0 0L10 10").TransformCSS("perspective(10px)", "")
+	assert.Equal(t, "SvgPath: unknown transform function `perspective` (at pos 0)", sp.Err().Error())
+}
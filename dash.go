@@ -0,0 +1,113 @@
+package svgpath
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Upper limit of dashes and gaps
+const dashMaxCount = 1000000
+
+// Convert path to its dashes, following `stroke-dasharray` and
+// `stroke-dashoffset` rules: odd-length pattern is repeated twice,
+// pattern restarts at every subpath and goes on through `z`. Every dash
+// becomes a separate subpath, curves and arcs are cut, not flattened.
+// Zero-length dashes become zero-length lines, drawn as dots by round
+// and square caps.
+//
+// Empty or all-zero pattern gives the path as is. Negative values are
+// an error, and so is a pattern too short for the path length, giving
+// over a million dashes and gaps. sp is not modified.
+//
+func (sp *SvgPath) Dash(pattern []float64, offset float64) *SvgPath {
+	if sp.err != nil {
+		return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: sp.err}
+	}
+
+	sum := 0.0
+	for _, v := range pattern {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			err := errors.Errorf("SvgPath: bad value %g in dash pattern", v)
+			return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: err}
+		}
+		sum += v
+	}
+	if sum == 0 {
+		return sp.Clone()
+	}
+	if len(pattern)%2 != 0 {
+		pattern = append(append([]float64{}, pattern...), pattern...)
+		sum *= 2
+	}
+
+	pm, err := NewPathMeasure(sp)
+	if err != nil {
+		return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: err}
+	}
+
+	// every subpath can start with a part of the pattern
+	if count := (pm.total/sum + float64(len(pm.contours))) * float64(len(pattern)); count > dashMaxCount {
+		err := errors.Errorf("SvgPath: dash pattern is too short for path length %g", pm.total)
+		return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: err}
+	}
+
+	// position in pattern at the subpath start
+	phase := math.Mod(offset, sum)
+	if phase < 0 {
+		phase += sum
+	}
+	first := 0
+	for phase > 0 && phase >= pattern[first] {
+		phase -= pattern[first]
+		first = (first + 1) % len(pattern)
+	}
+
+	segments := []*Segment{}
+	for _, c := range pm.contours {
+		if c.first == c.end {
+			continue
+		}
+		pos, _ := pm.primRange(c.first)
+		_, end := pm.primRange(c.end - 1)
+
+		i := first
+		length := pattern[i] - phase
+		for pos < end {
+			// even items are dashes, odd ones are gaps
+			if i%2 == 0 {
+				if length > 0 {
+					segments = pm.appendContourPart(segments, c, pos, math.Min(pos+length, end))
+				} else {
+					// zero-length dash is still drawn by round and
+					// square caps
+					p := pm.contourPoint(c, pos)
+					segments = append(segments,
+						&Segment{Command: "M", Params: []float64{p.X, p.Y}},
+						&Segment{Command: "L", Params: []float64{p.X, p.Y}})
+				}
+			}
+			pos += length
+			i = (i + 1) % len(pattern)
+			length = pattern[i]
+		}
+	}
+
+	return &SvgPath{segments: segments, stack: []*Matrix{}}
+}
+
+// Point of contour at distance
+//
+func (pm *PathMeasure) contourPoint(c measureContour, distance float64) Point {
+	n := c.end - c.first
+	i := c.first + sort.Search(n, func(k int) bool {
+		_, end := pm.primRange(c.first + k)
+		return end >= distance
+	})
+	if i == c.end {
+		i--
+	}
+	x, y := pm.prims[i].at(pm.primParam(i, distance))
+	return Point{x, y}
+}
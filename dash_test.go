@@ -0,0 +1,103 @@
+package svgpath
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashLines(t *testing.T) {
+	sp := Parse("M0 0H20")
+	assert.Equal(t, "M0 0L5 0M10 0L15 0", sp.Dash([]float64{5, 5}, 0).ToString())
+	assert.Equal(t, "M0 0L3 0M8 0L13 0M18 0L20 0", sp.Dash([]float64{5, 5}, 2).ToString(), "offset")
+	assert.Equal(t, "M2 0L7 0M12 0L17 0", sp.Dash([]float64{5, 5}, -2).ToString(), "negative offset")
+
+	// odd pattern is repeated: 4 2 1 4 2 1
+	assert.Equal(t, "M0 0L4 0M6 0L7 0M11 0L13 0M14 0L18 0", sp.Dash([]float64{4, 2, 1}, 0).ToString())
+}
+
+func TestDashSubpaths(t *testing.T) {
+	// pattern restarts at every subpath
+	sp := Parse("M0 0H8M0 10H8")
+	assert.Equal(t, "M0 0L3 0M6 0L8 0M0 10L3 10M6 10L8 10", sp.Dash([]float64{3, 3}, 0).ToString())
+
+	// dashes go on through the closing line
+	sp = Parse("M0 0H10V10H0Z")
+	assert.Equal(t, "M0 0L10 0 10 5M10 10L0 10 0 5", sp.Dash([]float64{15, 5}, 0).ToString())
+
+	// dash covering a closed subpath keeps it closed
+	assert.Equal(t, "M0 0L10 0 10 10 0 10Z", sp.Dash([]float64{100, 5}, 0).ToString())
+}
+
+func TestDashCurves(t *testing.T) {
+	sp := Parse("M10 0A10 10 0 1 1 -10 0C-10 20 10 20 10 0")
+	dashed := sp.Dash([]float64{3, 1}, 0)
+
+	commands := map[string]bool{}
+	for _, s := range dashed.Segments() {
+		commands[s.Command] = true
+	}
+	assert.Equal(t, map[string]bool{"M": true, "A": true, "C": true}, commands, "no flattening")
+	assert.InDelta(t, sp.Length()*3/4, dashed.Length(), 3, "three quarters, up to the last dash")
+}
+
+func TestDashSpecialPatterns(t *testing.T) {
+	sp := Parse("M0 0H20").Translate(1, 1)
+	assert.Equal(t, "M1 1H21", sp.Dash(nil, 0).ToString(), "empty pattern")
+	assert.Equal(t, "M1 1H21", sp.Dash([]float64{0, 0}, 0).ToString(), "zero pattern")
+	assert.Equal(t, "M1 1L1 1M6 1L11 1", sp.Dash([]float64{0, 5, 5, 100}, 0).ToString(), "zero dash is a dot")
+	assert.Equal(t, "M1 1L1 1M6 1L6 1M11 1L11 1M16 1L16 1", sp.Dash([]float64{0, 5}, 0).ToString(), "dots")
+	assert.Equal(t, "M3 1L3 1M8 1L8 1M13 1L13 1M18 1L18 1", sp.Dash([]float64{0, 5}, 3).Round(6).ToString(), "dots with offset")
+
+	// dot at the subpath start, not at the end of the previous one
+	assert.Equal(t, "M0 0L0 0M5 0L5 0M50 50L50 50M50 55L50 55",
+		Parse("M0 0H10M50 50V60").Dash([]float64{0, 5}, 0).ToString())
+
+	assert.NotNil(t, sp.Dash([]float64{5, -1}, 0).Err())
+
+	// too many dashes, or a pattern lost in rounding of positions
+	long := Parse("M0 0H1000000")
+	assert.NotNil(t, long.Dash([]float64{1e-9}, 0).Err())
+	assert.NotNil(t, Parse("M1e20 0H2e20").Dash([]float64{1, 1}, 0).Err())
+	assert.Nil(t, long.Dash([]float64{10, 10}, 0).Err())
+	assert.NotNil(t, Parse("M0 0").Matrix([]float64{1}).Dash([]float64{1}, 0).Err())
+}
+
+// long contour of many short segments, dashes cover several of them
+func dashLongContour(n int) *SvgPath {
+	var b strings.Builder
+	b.WriteString("M0 0")
+	for i := 0; i < n; i++ {
+		b.WriteString("l1.5-2.5")
+	}
+	return Parse(b.String())
+}
+
+func TestDashLongContour(t *testing.T) {
+	if testing.Short() {
+		t.Skip("long contour")
+	}
+	sp := dashLongContour(1000000)
+	dashed := sp.Dash([]float64{3, 3}, 0)
+	assert.Nil(t, dashed.Err())
+
+	pm, err := NewPathMeasure(sp)
+	assert.Nil(t, err)
+	dashes := 0
+	for _, s := range dashed.Segments() {
+		if s.Command == "M" {
+			dashes++
+		}
+	}
+	assert.Equal(t, int(math.Ceil(pm.Length()/6)), dashes)
+}
+
+func BenchmarkDashLongContour(b *testing.B) {
+	sp := dashLongContour(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sp.Dash([]float64{3, 3}, 0)
+	}
+}
@@ -0,0 +1,198 @@
+package svgpath
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Path string with pending transforms applied, sp is not modified.
+// Same as ToString, path with a chain error gives the segments as they
+// were when the chain failed.
+//
+func (sp SvgPath) String() string {
+	return ToString(&sp)
+}
+
+// Implements encoding.TextMarshaler. Fails on a chain error. Value
+// receiver, so that SvgPath fields are encoded as well as *SvgPath.
+//
+func (sp SvgPath) MarshalText() ([]byte, error) {
+	if sp.err != nil {
+		return nil, sp.err
+	}
+	return []byte(ToString(&sp)), nil
+}
+
+// Implements encoding.TextUnmarshaler. Replaces the path with the parsed
+// one, dropping pending transforms and the chain error.
+//
+func (sp *SvgPath) UnmarshalText(text []byte) error {
+	segments, err := PathParseBytes(text)
+	if err != nil {
+		return err
+	}
+	*sp = SvgPath{segments: segments, stack: []*Matrix{}}
+	return nil
+}
+
+// Implements json.Marshaler, path is written as `d` string. Use
+// JSONArray for the array form.
+//
+func (sp SvgPath) MarshalJSON() ([]byte, error) {
+	text, err := sp.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// Implements json.Unmarshaler. Accepts both `d` string and the array
+// form, null is ignored.
+//
+func (sp *SvgPath) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '[' {
+		var items []jsonSegment
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		segments := make([]*Segment, len(items))
+		for i := range items {
+			segments[i] = (*Segment)(&items[i])
+		}
+		if len(segments) > 0 && segments[0].Cmd().Abs() != CmdMoveTo {
+			return errors.New("SvgPath: string should start with `M` or `m`")
+		}
+		if len(segments) > 0 {
+			// leading `m` is absolute, same as in the string form
+			segments[0].Command = "M"
+		}
+		*sp = SvgPath{segments: segments, stack: []*Matrix{}}
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return sp.UnmarshalText([]byte(text))
+}
+
+// JSONArray marshals the path in fontello array form:
+// `[["M",1,2],["L",3,4]]`.
+//
+//    type Glyph struct {
+//      Path svgpath.JSONArray `json:"path"`
+//    }
+//
+type JSONArray struct {
+	*SvgPath
+}
+
+func (a JSONArray) MarshalJSON() ([]byte, error) {
+	if a.SvgPath == nil {
+		return []byte("null"), nil
+	}
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	sp := a.SvgPath
+	if len(sp.stack) > 0 {
+		sp = sp.Clone()
+		sp.evaluateStack()
+	}
+	items := make([]jsonSegment, len(sp.segments))
+	for i, s := range sp.segments {
+		items[i] = jsonSegment(*s)
+	}
+	return json.Marshal(items)
+}
+
+func (a *JSONArray) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	if a.SvgPath == nil {
+		a.SvgPath = &SvgPath{}
+	}
+	return a.SvgPath.UnmarshalJSON(data)
+}
+
+// Segment in array form: `["M",1,2]`. Segment itself keeps the default
+// object encoding.
+type jsonSegment Segment
+
+func (s jsonSegment) MarshalJSON() ([]byte, error) {
+	items := make([]interface{}, 0, len(s.Params)+1)
+	items = append(items, s.Command)
+	for _, p := range s.Params {
+		items = append(items, p)
+	}
+	return json.Marshal(items)
+}
+
+// Read segment in array form. Command and params count are checked.
+//
+func (s *jsonSegment) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return errors.New("SvgPath: empty segment")
+	}
+
+	var seg Segment
+	if err := json.Unmarshal(items[0], &seg.Command); err != nil {
+		return errors.Wrap(err, "SvgPath: bad segment command")
+	}
+	seg.Params = make([]float64, len(items)-1)
+	for i, item := range items[1:] {
+		if err := json.Unmarshal(item, &seg.Params[i]); err != nil {
+			return errors.Wrapf(err, "SvgPath: bad param %d of %s", i+1, seg.Command)
+		}
+	}
+
+	if _, err := seg.Typed(); err != nil {
+		return err
+	}
+	*s = jsonSegment(seg)
+	return nil
+}
+
+// Implements sql.Scanner for text columns. NULL gives an empty path.
+//
+func (sp *SvgPath) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*sp = SvgPath{segments: []*Segment{}, stack: []*Matrix{}}
+		return nil
+	case string:
+		return sp.UnmarshalText([]byte(v))
+	case []byte:
+		return sp.UnmarshalText(v)
+	}
+	return errors.Errorf("SvgPath: cannot scan %T into path", src)
+}
+
+// Implements driver.Valuer, path is stored as `d` string. Nil path is
+// stored as NULL.
+//
+func (sp *SvgPath) Value() (driver.Value, error) {
+	if sp == nil {
+		return nil, nil
+	}
+	text, err := sp.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
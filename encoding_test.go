@@ -0,0 +1,134 @@
+package svgpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type glyph struct {
+	Name string    `json:"name"`
+	Path *SvgPath  `json:"path"`
+	Arr  JSONArray `json:"arr"`
+}
+
+func TestStringer(t *testing.T) {
+	sp := Parse("M10 10L20 20").Translate(5, 0)
+	assert.Equal(t, "M15 10L25 20", fmt.Sprint(sp))
+	assert.Equal(t, 1, len(sp.stack), "String doesn't apply transforms")
+
+	sp = Parse("M10 10L20 20").Transform("rotate(x)").Translate(5, 0)
+	assert.Equal(t, sp.ToString(), sp.String(), "same as ToString on a chain error")
+	assert.Equal(t, "M10 10L20 20", sp.String())
+}
+
+func TestText(t *testing.T) {
+	sp := &SvgPath{}
+	assert.Nil(t, sp.UnmarshalText([]byte("M1 2l3 4")))
+	text, err := sp.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, "M1 2l3 4", string(text))
+
+	assert.NotNil(t, sp.UnmarshalText([]byte("L1 2")))
+
+	_, err = Parse("M0 0").Matrix([]float64{1}).MarshalText()
+	assert.NotNil(t, err)
+}
+
+func TestJSON(t *testing.T) {
+	g := glyph{Name: "a", Path: Parse("M1 2L3 4").Scale(2, 2), Arr: JSONArray{Parse("M1 2l3 4")}}
+	data, err := json.Marshal(g)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"a","path":"M2 4L6 8","arr":[["M",1,2],["l",3,4]]}`, string(data))
+
+	var back glyph
+	assert.Nil(t, json.Unmarshal(data, &back))
+	assert.Equal(t, "M2 4L6 8", back.Path.String())
+	assert.Equal(t, "M1 2l3 4", back.Arr.String())
+
+	// both forms are accepted
+	var sp SvgPath
+	assert.Nil(t, json.Unmarshal([]byte(`[["M",1,2],["A",1,1,0,0,1,5,5],["z"]]`), &sp))
+	assert.Equal(t, "M1 2A1 1 0 0 1 5 5z", sp.String())
+
+	// leading `m` reads the same in both forms
+	var fromString, fromArray SvgPath
+	assert.Nil(t, json.Unmarshal([]byte(`"m1 2l3 4"`), &fromString))
+	assert.Nil(t, json.Unmarshal([]byte(`[["m",1,2],["l",3,4]]`), &fromArray))
+	assert.Equal(t, fromString.Segments(), fromArray.Segments())
+	data, err = json.Marshal(JSONArray{&fromArray})
+	assert.Nil(t, err)
+	assert.Equal(t, `[["M",1,2],["l",3,4]]`, string(data))
+	var again JSONArray
+	assert.Nil(t, json.Unmarshal(data, &again))
+	assert.Equal(t, fromString.Segments(), again.Segments())
+
+	assert.NotNil(t, json.Unmarshal([]byte(`[["L",1,2]]`), &sp), "first command")
+	assert.NotNil(t, json.Unmarshal([]byte(`[["M",1]]`), &sp), "params count")
+	assert.NotNil(t, json.Unmarshal([]byte(`[["X",1,2]]`), &sp), "bad command")
+	assert.NotNil(t, json.Unmarshal([]byte(`[["M","1",2]]`), &sp), "bad param")
+	assert.NotNil(t, json.Unmarshal([]byte(`"M1"`), &sp), "bad string")
+}
+
+func TestJSONValueField(t *testing.T) {
+	type shape struct {
+		Path SvgPath `json:"path"`
+	}
+
+	data, err := json.Marshal(shape{Path: *Parse("M1 2L3 4").Scale(2, 2)})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"path":"M2 4L6 8"}`, string(data))
+
+	data, err = json.Marshal(&shape{Path: *Parse("M1 2L3 4")})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"path":"M1 2L3 4"}`, string(data))
+
+	var back shape
+	assert.Nil(t, json.Unmarshal(data, &back))
+	assert.Equal(t, "M1 2L3 4", back.Path.String())
+
+	_, err = json.Marshal(shape{Path: *Parse("M1 2").Matrix([]float64{1})})
+	assert.NotNil(t, err)
+}
+
+func TestSegmentJSON(t *testing.T) {
+	// array form is private to SvgPath
+	data, err := json.Marshal(jsonSegment{Command: "R", Params: []float64{1, 2.5, 3, 4}})
+	assert.Nil(t, err)
+	assert.Equal(t, `["R",1,2.5,3,4]`, string(data))
+
+	var s jsonSegment
+	assert.Nil(t, json.Unmarshal(data, &s))
+	assert.Equal(t, jsonSegment{Command: "R", Params: []float64{1, 2.5, 3, 4}}, s)
+	assert.NotNil(t, json.Unmarshal([]byte(`[]`), &s))
+
+	// Segment keeps the default encoding, by value and by pointer
+	seg := Segment{Command: "M", Params: []float64{1, 2}}
+	for _, v := range []interface{}{seg, &seg, []Segment{seg}, []*Segment{&seg}} {
+		data, err = json.Marshal(v)
+		assert.Nil(t, err)
+		assert.Contains(t, string(data), `{"Command":"M","Params":[1,2]}`)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	var sp SvgPath
+	assert.Nil(t, sp.Scan("M1 2"))
+	assert.Equal(t, "M1 2", sp.String())
+	assert.Nil(t, sp.Scan([]byte("M3 4")))
+	assert.Equal(t, "M3 4", sp.String())
+	assert.Nil(t, sp.Scan(nil))
+	assert.Equal(t, "", sp.String())
+	assert.NotNil(t, sp.Scan(42))
+
+	v, err := Parse("M1 2").Translate(1, 1).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "M2 3", v)
+
+	var nilPath *SvgPath
+	v, err = nilPath.Value()
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+}
@@ -0,0 +1,186 @@
+package svgpath
+
+import (
+	"math"
+)
+
+// limits for degenerate tolerance values
+const (
+	flattenMinTolerance = 1e-9
+	flattenMaxDepth     = 16
+	flattenMaxSteps     = 1 << 16
+)
+
+// Polyline approximating a subpath. Closed polylines don't repeat their
+// first point, the line back to it is implied by Closed.
+type Polyline struct {
+	Points []Point
+	Closed bool
+}
+
+// Approximate path with polylines, one per subpath, deviating from
+// curves and arcs by no more than tolerance. Pending transforms are
+// applied, sp is not modified. Tolerance below 1e-9 is raised to it.
+// Path with an error gives nil.
+//
+func (sp *SvgPath) Flatten(tolerance float64) []Polyline {
+	if sp.err != nil {
+		return nil
+	}
+
+	contours := sp.contours()
+	res := make([]Polyline, len(contours))
+	for i := range contours {
+		res[i] = flattenContour(&contours[i], tolerance)
+	}
+	return res
+}
+
+// Same as Flatten, as a path of `M`, `L` and `Z` commands
+//
+func (sp *SvgPath) FlattenPath(tolerance float64) *SvgPath {
+	if sp.err != nil {
+		return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: sp.err}
+	}
+
+	segments := []*Segment{}
+	contours := sp.contours()
+	for i := range contours {
+		pl := flattenContour(&contours[i], tolerance)
+		segments = append(segments, &Segment{Command: "M", Params: []float64{pl.Points[0].X, pl.Points[0].Y}})
+		for _, p := range pl.Points[1:] {
+			segments = append(segments, &Segment{Command: "L", Params: []float64{p.X, p.Y}})
+		}
+		if pl.Closed {
+			segments = append(segments, &Segment{Command: "Z", Params: []float64{}})
+		}
+	}
+	return &SvgPath{segments: segments, stack: []*Matrix{}}
+}
+
+func flattenContour(c *contour, tolerance float64) Polyline {
+	if !(tolerance >= flattenMinTolerance) {
+		tolerance = flattenMinTolerance
+	}
+	f := flattener{tolerance: tolerance, points: []Point{{c.startX, c.startY}}}
+	for i := range c.prims {
+		f.primitive(&c.prims[i])
+	}
+
+	points := f.points
+	if c.closed && len(points) > 1 && points[len(points)-1] == points[0] {
+		points = points[:len(points)-1]
+	}
+	return Polyline{Points: points, Closed: c.closed}
+}
+
+type flattener struct {
+	tolerance float64
+	points    []Point
+}
+
+func (f *flattener) add(x, y float64) {
+	if last := f.points[len(f.points)-1]; last.X == x && last.Y == y {
+		return
+	}
+	f.points = append(f.points, Point{x, y})
+}
+
+func (f *flattener) primitive(pr *primitive) {
+	switch pr.kind {
+	case primQuad, primCubic:
+		f.bezier(*pr, 0)
+	case primArc:
+		f.arc(pr)
+	default:
+		f.add(pr.p[2], pr.p[3])
+	}
+}
+
+// Curve is split evenly by parameter into the estimated number of
+// steps, pieces which are still not flat enough are split further.
+// Parts of uneven curves can need fewer steps in total, then halves are
+// flattened separately.
+//
+func (f *flattener) bezier(pr primitive, depth int) {
+	deviation := bezierDeviation(&pr)
+	if depth >= flattenMaxDepth || deviation <= f.tolerance {
+		f.add(pr.end())
+		return
+	}
+
+	steps := f.bezierSteps(&pr, deviation)
+	head, tail := pr.sub(0, 0.5), pr.sub(0.5, 1)
+	if f.bezierSteps(&head, bezierDeviation(&head))+f.bezierSteps(&tail, bezierDeviation(&tail)) < steps {
+		f.bezier(head, depth+1)
+		f.bezier(tail, depth+1)
+		return
+	}
+
+	for i := 0; i < steps; i++ {
+		f.bezier(pr.sub(float64(i)/float64(steps), float64(i+1)/float64(steps)), depth+1)
+	}
+}
+
+// Upper bound of the curve distance to its chord. Curve points are
+// weighted sums of control points, so distance to the chord is at most
+// 2t(1-t) of the control point distance for quadratic curves and
+// 3t(1-t) of the farthest one for cubics. The flatness bound by Roger
+// Willcocks is tighter for curved cubics.
+//
+func bezierDeviation(pr *primitive) float64 {
+	p := &pr.p
+	if pr.kind == primQuad {
+		return segmentDist(p[2], p[3], p[0], p[1], p[4], p[5]) / 2
+	}
+
+	hull := 0.75 * math.Max(
+		segmentDist(p[2], p[3], p[0], p[1], p[6], p[7]),
+		segmentDist(p[4], p[5], p[0], p[1], p[6], p[7]))
+	ux := math.Max(math.Abs(3*p[2]-2*p[0]-p[6]), math.Abs(3*p[4]-p[0]-2*p[6]))
+	uy := math.Max(math.Abs(3*p[3]-2*p[1]-p[7]), math.Abs(3*p[5]-p[1]-2*p[7]))
+	return math.Min(hull, math.Hypot(ux, uy)/4)
+}
+
+// Deviation shrinks about with the square of the parameter step
+//
+func (f *flattener) bezierSteps(pr *primitive, deviation float64) int {
+	if deviation <= f.tolerance {
+		return 1
+	}
+	return int(math.Min(math.Ceil(math.Sqrt(deviation/f.tolerance)), flattenMaxSteps))
+}
+
+// Arc is split evenly by angle: a chord over angle d of a unit circle
+// deviates by 1 - cos(d/2), ellipse stretches it by its major radius
+// at most
+//
+func (f *flattener) arc(pr *primitive) {
+	a := &pr.arc
+	uu := a.ux*a.ux + a.uy*a.uy
+	vv := a.vx*a.vx + a.vy*a.vy
+	uv := a.ux*a.vx + a.uy*a.vy
+	major := math.Sqrt((uu+vv)/2 + math.Hypot((uu-vv)/2, uv))
+
+	steps := 1
+	if f.tolerance < major {
+		maxAngle := 2 * math.Acos(1-f.tolerance/major)
+		steps = int(math.Min(math.Ceil(math.Abs(a.dtheta)/maxAngle), flattenMaxSteps))
+	}
+
+	for i := 1; i < steps; i++ {
+		f.add(pr.at(float64(i) / float64(steps)))
+	}
+	f.add(pr.p[2], pr.p[3])
+}
+
+// Distance from (x, y) to the segment (x0, y0) - (x1, y1)
+//
+func segmentDist(x, y, x0, y0, x1, y1 float64) float64 {
+	dx, dy := x1-x0, y1-y0
+	t := 0.0
+	if l2 := dx*dx + dy*dy; l2 > 0 {
+		t = math.Max(0, math.Min(1, ((x-x0)*dx+(y-y0)*dy)/l2))
+	}
+	return math.Hypot(x-x0-t*dx, y-y0-t*dy)
+}
@@ -0,0 +1,110 @@
+package svgpath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenLines(t *testing.T) {
+	sp := Parse("M0 0H10V10M20 20l5 5z")
+	assert.Equal(t, []Polyline{
+		{Points: []Point{{0, 0}, {10, 0}, {10, 10}}},
+		{Points: []Point{{20, 20}, {25, 25}}, Closed: true},
+	}, sp.Flatten(0.1))
+	assert.Equal(t, "M0 0L10 0 10 10M20 20L25 25Z", sp.FlattenPath(0.1).ToString())
+}
+
+func TestFlattenSubpaths(t *testing.T) {
+	assert.Equal(t, []Polyline{{Points: []Point{{5, 5}}}}, Parse("M5 5").Flatten(1), "lone moveto")
+	assert.Equal(t, "M5 5", Parse("M5 5").FlattenPath(1).ToString())
+	assert.Equal(t, "M0 0ZM5 5L6 5", Parse("M0 0zM5 5h1").FlattenPath(1).ToString())
+	assert.Equal(t, []Polyline{{Points: []Point{{0, 0}}, Closed: true}}, Parse("M0 0z").Flatten(1))
+	assert.Equal(t, []Polyline{}, Parse("").Flatten(1))
+
+	// open subpath ending at its start keeps the last point
+	assert.Equal(t, []Polyline{
+		{Points: []Point{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+	}, Parse("M0 0H10V10L0 0").Flatten(1))
+	assert.Equal(t, []Polyline{
+		{Points: []Point{{0, 0}, {10, 0}, {10, 10}}, Closed: true},
+	}, Parse("M0 0H10V10Z").Flatten(1))
+	assert.Equal(t, []Polyline{
+		{Points: []Point{{0, 0}, {10, 0}, {10, 10}}, Closed: true},
+	}, Parse("M0 0H10V10L0 0Z").Flatten(1), "closing line of zero length")
+	assert.Equal(t, "M0 0L10 0 10 10 0 0", Parse("M0 0H10V10L0 0").FlattenPath(1).ToString())
+	assert.Equal(t, "M0 0L10 0 10 10Z", Parse("M0 0H10V10L0 0Z").FlattenPath(1).ToString())
+}
+
+// max distance from the curve samples to the polyline
+func flattenDeviation(sp *SvgPath, points []Point) float64 {
+	pm, _ := NewPathMeasure(sp)
+	worst := 0.0
+	for i := 0; i <= 1000; i++ {
+		p := pm.PointAt(pm.Length() * float64(i) / 1000)
+		best := math.Inf(1)
+		for j := 1; j < len(points); j++ {
+			a, b := points[j-1], points[j]
+			best = math.Min(best, segmentDist(p.X, p.Y, a.X, a.Y, b.X, b.Y))
+		}
+		worst = math.Max(worst, best)
+	}
+	return worst
+}
+
+func TestFlattenCurves(t *testing.T) {
+	paths := []string{
+		"M0 0Q50 100 100 0",
+		"M0 0C0 100 100 100 100 0",
+		"M0 0S50 100 100 0T200 0",
+		"M0 0A50 50 0 1 1 100 0",
+		"M0 0A80 20 30 0 0 100 50",
+	}
+	for _, path := range paths {
+		sp := Parse(path)
+		for _, tolerance := range []float64{1, 0.1, 0.01} {
+			flat := sp.Flatten(tolerance)
+			assert.Len(t, flat, 1, path)
+			assert.True(t, flattenDeviation(sp, flat[0].Points) <= tolerance, "%s: %g", path, tolerance)
+		}
+	}
+}
+
+func TestFlattenMinimal(t *testing.T) {
+	// straight curves give their chord
+	assert.Equal(t, []Polyline{{Points: []Point{{0, 0}, {10, 0}}}}, Parse("M0 0Q5 0 10 0").Flatten(0.1))
+	assert.Equal(t, []Polyline{{Points: []Point{{0, 0}, {9, 0}}}}, Parse("M0 0C3 0 6 0 9 0").Flatten(0.1))
+
+	// half circle of radius 50: chord over angle a deviates by 50*(1-cos(a/2))
+	sp := Parse("M0 0A50 50 0 0 1 100 0")
+	flat := sp.Flatten(1)[0].Points
+	assert.Equal(t, int(math.Ceil(math.Pi/(2*math.Acos(1-1.0/50))))+1, len(flat))
+
+	// parabola is split evenly: chord over step h deviates by 200*h^2/4
+	assert.Len(t, Parse("M0 0Q50 100 100 0").Flatten(1)[0].Points, 9)
+
+	// coarser tolerance gives fewer points
+	sp = Parse("M0 0C0 100 100 100 100 0")
+	assert.True(t, len(sp.Flatten(1)[0].Points) < len(sp.Flatten(0.01)[0].Points))
+	assert.True(t, len(sp.Flatten(0.01)[0].Points) < 100)
+}
+
+func TestFlattenBadTolerance(t *testing.T) {
+	sp := Parse("M0 0C0 1 1 1 1 0A1 1 0 0 1 2 0")
+	for _, tolerance := range []float64{0, -1, math.NaN()} {
+		points := sp.Flatten(tolerance)[0].Points
+		assert.Equal(t, Point{2, 0}, points[len(points)-1])
+	}
+}
+
+func TestFlattenTransformed(t *testing.T) {
+	sp := Parse("M0 0Q5 0 10 0").Scale(2, 2)
+	assert.Equal(t, []Polyline{{Points: []Point{{0, 0}, {20, 0}}}}, sp.Flatten(0.1))
+}
+
+func TestFlattenError(t *testing.T) {
+	sp := Parse("M0 0X")
+	assert.Nil(t, sp.Flatten(1))
+	assert.Error(t, sp.FlattenPath(1).Err())
+}
@@ -0,0 +1,205 @@
+package svgpath
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// FormatOptions control how path data is written by ToStringWithOptions
+type FormatOptions struct {
+	// Round numbers to Precision digits after the decimal point. When
+	// Fixed is not set (so the zero FormatOptions too) or Precision is
+	// negative, numbers are written in the shortest form that reads
+	// back exactly.
+	Fixed     bool
+	Precision int
+
+	// Write `.5` instead of `0.5`
+	StripLeadingZero bool
+
+	// Drop separators that the parser doesn't need: before `-` and
+	// before `.5` following a number that already has a dot
+	CompactSeparators bool
+
+	// Separate numbers with `,` instead of space
+	Comma bool
+
+	// Don't repeat command letter for consecutive segments of the same
+	// command (`M` and `R` are always written)
+	ElideRepeated bool
+
+	// Also drop `L`/`l` right after `M`/`m`, as extra moveto pairs
+	// are read as lineto
+	ImplicitLineTo bool
+
+	// Write arc flags without separators: `a1 1 0 011 1`
+	CompactArcFlags bool
+}
+
+// Options matching ToString output
+var DefaultFormatOptions = FormatOptions{
+	CompactSeparators: true,
+	ElideRepeated:     true,
+}
+
+// Options producing the shortest output at the given precision
+//
+func MinifyFormatOptions(precision int) FormatOptions {
+	return FormatOptions{
+		Fixed:             precision >= 0,
+		Precision:         precision,
+		StripLeadingZero:  true,
+		CompactSeparators: true,
+		ElideRepeated:     true,
+		ImplicitLineTo:    true,
+		CompactArcFlags:   true,
+	}
+}
+
+// Digits after the decimal point, -1 for the shortest form
+//
+func (opts FormatOptions) digits() int {
+	if !opts.Fixed || opts.Precision < 0 {
+		return -1
+	}
+	return opts.Precision
+}
+
+// Convert processed SVG Path back to string, formatted with opts
+//
+func (sp *SvgPath) ToStringWithOptions(opts FormatOptions) string {
+	sp.evaluateStack()
+
+	f := pathFormatter{opts: opts}
+	for _, s := range sp.segments {
+		f.segment(s.Command, s.Params)
+	}
+	return string(f.buf)
+}
+
+// pathFormatter writes segments one by one, keeping track of what is
+// needed to decide about separators and command letters
+type pathFormatter struct {
+	opts    FormatOptions
+	buf     []byte
+	scratch []byte // number being written
+
+	prevCmd string
+
+	// last written token was a number, and whether it had a dot
+	afterNumber bool
+	hasDot      bool
+
+	// next number doesn't need a separator (after single char arc flag)
+	noSeparator bool
+}
+
+// Check if cmd letter can be omitted after prevCmd
+//
+func (f *pathFormatter) canElide(cmd string) bool {
+	if !f.opts.ElideRepeated || f.prevCmd == "" {
+		return false
+	}
+	if cmd == f.prevCmd {
+		return cmd != "m" && cmd != "M" && cmd != "r" && cmd != "R"
+	}
+	return f.opts.ImplicitLineTo &&
+		(f.prevCmd == "M" && cmd == "L" || f.prevCmd == "m" && cmd == "l")
+}
+
+func (f *pathFormatter) segment(cmd string, params []float64) {
+	if f.canElide(cmd) {
+		// ML -> M, keep tracking it as M for the next segments
+		cmd = f.prevCmd
+	} else {
+		f.buf = append(f.buf, cmd...)
+		f.afterNumber = false
+		f.noSeparator = false
+	}
+	f.prevCmd = cmd
+
+	isArc := cmd == "a" || cmd == "A"
+	for i, param := range params {
+		if isArc && (i == 3 || i == 4) {
+			f.flag(param)
+			continue
+		}
+		if isArc && i == 2 && f.opts.digits() >= 0 {
+			// better precision for rotation, same as Round
+			f.opts.Precision += 2
+			f.number(param)
+			f.opts.Precision -= 2
+			continue
+		}
+		f.number(param)
+	}
+}
+
+func (f *pathFormatter) separator() {
+	if f.opts.Comma {
+		f.buf = append(f.buf, ',')
+	} else {
+		f.buf = append(f.buf, ' ')
+	}
+}
+
+func (f *pathFormatter) flag(value float64) {
+	if f.afterNumber && !(f.noSeparator && f.opts.CompactArcFlags) {
+		f.separator()
+	}
+	if value != 0 {
+		f.buf = append(f.buf, '1')
+	} else {
+		f.buf = append(f.buf, '0')
+	}
+	f.afterNumber = true
+	f.hasDot = false
+	f.noSeparator = true
+}
+
+func (f *pathFormatter) number(value float64) {
+	f.scratch = f.appendNumber(f.scratch[:0], value)
+	num := f.scratch
+
+	if f.afterNumber {
+		needSeparator := true
+		if f.noSeparator && f.opts.CompactArcFlags {
+			needSeparator = false
+		} else if f.opts.CompactSeparators {
+			needSeparator = !(num[0] == '-' || num[0] == '.' && f.hasDot)
+		}
+		if needSeparator {
+			f.separator()
+		}
+	}
+	f.buf = append(f.buf, num...)
+
+	f.afterNumber = true
+	f.noSeparator = false
+	f.hasDot = bytes.IndexByte(num, '.') >= 0
+}
+
+func (f *pathFormatter) appendNumber(b []byte, value float64) []byte {
+	var num []byte
+	if digits := f.opts.digits(); digits < 0 {
+		num = strconv.AppendFloat(b, value, 'g', -1, 64)
+	} else {
+		num = strconv.AppendFloat(b, toFixed(value, digits), 'f', -1, 64)
+	}
+	tail := num[len(b):]
+
+	// never write negative zero
+	if len(tail) == 2 && tail[0] == '-' && tail[1] == '0' {
+		return append(b, '0')
+	}
+
+	if f.opts.StripLeadingZero {
+		if len(tail) > 1 && tail[0] == '0' && tail[1] == '.' {
+			return append(b, tail[1:]...)
+		}
+		if len(tail) > 2 && tail[0] == '-' && tail[1] == '0' && tail[2] == '.' {
+			return append(append(b, '-'), tail[2:]...)
+		}
+	}
+	return num
+}
@@ -0,0 +1,60 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDefault(t *testing.T) {
+	sp, err := NewSvgPath("M 0.5 -0.5 L 10 10 L 20 -20 A 1 1 0 0 1 1e-5 2")
+	assert.Nil(t, err)
+	assert.Equal(t, sp.ToString(), sp.ToStringWithOptions(DefaultFormatOptions))
+	assert.Equal(t, "M0.5-0.5L10 10 20-20A1 1 0 0 1 1e-05 2", sp.ToString())
+}
+
+func TestFormatOptions(t *testing.T) {
+	sp, err := NewSvgPath("M 0.5 -0.5 L 10.123 10 L 0.5 0.25 l -0.5 -0.25 a 1 1 0 0 1 0.5 2 Z")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "M0.5 -0.5L10.123 10L0.5 0.25l-0.5 -0.25a1 1 0 0 1 0.5 2Z",
+		sp.ToStringWithOptions(FormatOptions{}), "no optimizations")
+
+	assert.Equal(t, "M0.5,-0.5L10.1,10,0.5,0.3l-0.5,-0.3a1,1,0,0,1,0.5,2Z",
+		sp.ToStringWithOptions(FormatOptions{Fixed: true, Precision: 1, Comma: true, ElideRepeated: true}), "precision and commas")
+
+	assert.Equal(t, "M.5-.5L10.12 10 .5.25l-.5-.25a1 1 0 0 1 .5 2Z",
+		sp.ToStringWithOptions(FormatOptions{Fixed: true, Precision: 2, StripLeadingZero: true, CompactSeparators: true, ElideRepeated: true}), "leading zeros")
+
+	assert.Equal(t, "M.5-.5 10.1 10 .5.3l-.5-.3a1 1 0 01.5 2Z",
+		sp.ToStringWithOptions(MinifyFormatOptions(1)), "minify")
+}
+
+func TestFormatEdgeCases(t *testing.T) {
+	sp, err := NewSvgPath("M0 0l-0.001 0.001M1 1m1 1l1 1R1 1 2 2R3 3 4 4")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0l0 0M1 1m1 1 1 1R1 1 2 2R3 3 4 4", sp.ToStringWithOptions(MinifyFormatOptions(1)), "no negative zero, keep M and R")
+
+	sp, err = NewSvgPath("M0 0A1 1 0 1 1 -5 5 1 1 0 0 0 .5 .5")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0A1 1 0 11-5 5 1 1 0 00.5.5", sp.ToStringWithOptions(MinifyFormatOptions(1)))
+
+	// compact output should read back
+	out, err := NewSvgPath(sp.ToStringWithOptions(MinifyFormatOptions(1)))
+	assert.Nil(t, err)
+	assert.Equal(t, sp.ToString(), out.ToString())
+}
+
+func TestFormatOptionsZeroValue(t *testing.T) {
+	sp := Parse("M0.123 1.5L2.25 3.75")
+	assert.Equal(t, "M0.123 1.5L2.25 3.75", sp.ToStringWithOptions(FormatOptions{}), "zero value keeps all digits")
+	assert.Equal(t, "M0.123 1.5L2.25 3.75", sp.ToStringWithOptions(FormatOptions{Precision: 0}), "precision needs Fixed")
+	assert.Equal(t, "M0 2L2 4", sp.ToStringWithOptions(FormatOptions{Fixed: true, Precision: 0}), "integers")
+	assert.Equal(t, "M0.123 1.5L2.25 3.75", sp.ToStringWithOptions(FormatOptions{Fixed: true, Precision: -1}), "negative precision")
+}
+
+func TestFormatArcRotationPrecision(t *testing.T) {
+	sp, err := NewSvgPath("M0 0A2 1 12.3456 0 1 5 5")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0A2 1 12.35 015 5", sp.ToStringWithOptions(MinifyFormatOptions(0)))
+}
@@ -0,0 +1,383 @@
+package svgpath
+
+import (
+	"math"
+)
+
+// Point on the plane
+type Point struct {
+	X, Y float64
+}
+
+// Kind of a geometric primitive of a path
+type primKind int
+
+const (
+	primLine primKind = iota
+	primQuad
+	primCubic
+	primArc
+)
+
+// Elliptic arc in center parameterization. Point at angle t is
+// (cx + ux*cos(t) + vx*sin(t), cy + uy*cos(t) + vy*sin(t)), t runs from
+// theta1 to theta1+dtheta. Any affine transform of an arc keeps this
+// form, so transformed arcs need no special treatment.
+type ellipseArc struct {
+	cx, cy         float64
+	ux, uy, vx, vy float64
+	theta1, dtheta float64
+}
+
+func (a *ellipseArc) at(t float64) (float64, float64) {
+	c, s := math.Cos(t), math.Sin(t)
+	return a.cx + a.ux*c + a.vx*s, a.cy + a.uy*c + a.vy*s
+}
+
+// derivative by t
+func (a *ellipseArc) deriv(t float64) (float64, float64) {
+	c, s := math.Cos(t), math.Sin(t)
+	return -a.ux*s + a.vx*c, -a.uy*s + a.vy*c
+}
+
+// Arc parameter of angle t: 0 at the arc start, 1 at its end, above 1
+// for angles outside the arc sweep
+//
+func (a *ellipseArc) param(t float64) float64 {
+	d := t - a.theta1
+	if a.dtheta < 0 {
+		d = -d
+	}
+	d = math.Mod(d, TAU)
+	if d < 0 {
+		d += TAU
+	}
+	return d / math.Abs(a.dtheta)
+}
+
+// Line, Bézier curve or elliptic arc with absolute coordinates.
+// Bézier control points are in p: 2 points for lines, 3 for quadratic,
+// 4 for cubic curves. Arcs keep their end points in p[0:4].
+type primitive struct {
+	kind primKind
+	p    [8]float64
+	arc  ellipseArc
+
+	seg     int  // index of the path segment it comes from
+	closing bool // line added by `z`
+}
+
+func (pr *primitive) start() (float64, float64) {
+	return pr.p[0], pr.p[1]
+}
+
+func (pr *primitive) end() (float64, float64) {
+	switch pr.kind {
+	case primQuad:
+		return pr.p[4], pr.p[5]
+	case primCubic:
+		return pr.p[6], pr.p[7]
+	}
+	return pr.p[2], pr.p[3]
+}
+
+// Point at parameter t in [0, 1]
+//
+func (pr *primitive) at(t float64) (float64, float64) {
+	p := &pr.p
+	mt := 1 - t
+	switch pr.kind {
+	case primQuad:
+		a, b, c := mt*mt, 2*mt*t, t*t
+		return a*p[0] + b*p[2] + c*p[4], a*p[1] + b*p[3] + c*p[5]
+	case primCubic:
+		a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+		return a*p[0] + b*p[2] + c*p[4] + d*p[6], a*p[1] + b*p[3] + c*p[5] + d*p[7]
+	case primArc:
+		if t == 1 {
+			return p[2], p[3]
+		}
+		return pr.arc.at(pr.arc.theta1 + t*pr.arc.dtheta)
+	}
+	return mt*p[0] + t*p[2], mt*p[1] + t*p[3]
+}
+
+// Derivative by t at parameter t in [0, 1]
+//
+func (pr *primitive) deriv(t float64) (float64, float64) {
+	p := &pr.p
+	mt := 1 - t
+	switch pr.kind {
+	case primQuad:
+		return 2 * (mt*(p[2]-p[0]) + t*(p[4]-p[2])), 2 * (mt*(p[3]-p[1]) + t*(p[5]-p[3]))
+	case primCubic:
+		a, b, c := 3*mt*mt, 6*mt*t, 3*t*t
+		return a*(p[2]-p[0]) + b*(p[4]-p[2]) + c*(p[6]-p[4]),
+			a*(p[3]-p[1]) + b*(p[5]-p[3]) + c*(p[7]-p[5])
+	case primArc:
+		dx, dy := pr.arc.deriv(pr.arc.theta1 + t*pr.arc.dtheta)
+		return dx * pr.arc.dtheta, dy * pr.arc.dtheta
+	}
+	return p[2] - p[0], p[3] - p[1]
+}
+
+// contour is a subpath: a start point and primitives drawn from it
+type contour struct {
+	startX, startY float64
+	prims          []primitive
+	closed         bool
+}
+
+// geometryBuilder is a PathHandler collecting contours, with an affine
+// transform applied on the fly
+type geometryBuilder struct {
+	m        []float64 // nil for identity
+	contours []contour
+	seg      int // index of the segment being resolved
+
+	// current point and subpath start before transform, needed for arcs
+	x, y           float64
+	startX, startY float64
+}
+
+func (g *geometryBuilder) apply(x, y float64) (float64, float64) {
+	if g.m == nil {
+		return x, y
+	}
+	m := g.m
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+func (g *geometryBuilder) last() *contour {
+	return &g.contours[len(g.contours)-1]
+}
+
+// end point of the current contour, transformed
+func (g *geometryBuilder) current() (float64, float64) {
+	c := g.last()
+	if len(c.prims) == 0 {
+		return c.startX, c.startY
+	}
+	return c.prims[len(c.prims)-1].end()
+}
+
+// Add primitive with the given (untransformed) points after the current one
+//
+func (g *geometryBuilder) add(kind primKind, pts ...float64) {
+	pr := primitive{kind: kind, seg: g.seg}
+	pr.p[0], pr.p[1] = g.current()
+	for i := 0; i < len(pts); i += 2 {
+		pr.p[i+2], pr.p[i+3] = g.apply(pts[i], pts[i+1])
+	}
+	c := g.last()
+	c.prims = append(c.prims, pr)
+	g.x, g.y = pts[len(pts)-2], pts[len(pts)-1]
+}
+
+func (g *geometryBuilder) MoveTo(x, y float64) {
+	tx, ty := g.apply(x, y)
+	g.contours = append(g.contours, contour{startX: tx, startY: ty})
+	g.x, g.y = x, y
+	g.startX, g.startY = x, y
+}
+
+func (g *geometryBuilder) LineTo(x, y float64) {
+	g.add(primLine, x, y)
+}
+
+func (g *geometryBuilder) QuadTo(x1, y1, x, y float64) {
+	g.add(primQuad, x1, y1, x, y)
+}
+
+func (g *geometryBuilder) CubicTo(x1, y1, x2, y2, x, y float64) {
+	g.add(primCubic, x1, y1, x2, y2, x, y)
+}
+
+func (g *geometryBuilder) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) {
+	x1, y1 := g.x, g.y
+
+	// Same radii correction as in a2c
+	sin_phi := math.Sin(rotation * torad)
+	cos_phi := math.Cos(rotation * torad)
+	x1p := cos_phi*(x1-x)/2 + sin_phi*(y1-y)/2
+	y1p := -sin_phi*(x1-x)/2 + cos_phi*(y1-y)/2
+
+	if x1p == 0 && y1p == 0 {
+		// end point is the start point, the arc is omitted
+		g.x, g.y = x, y
+		return
+	}
+	if rx == 0 || ry == 0 {
+		g.add(primLine, x, y)
+		return
+	}
+
+	rx = math.Abs(rx)
+	ry = math.Abs(ry)
+	lambda := (x1p*x1p)/(rx*rx) + (y1p*y1p)/(ry*ry)
+	if lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+
+	cc := get_arc_center(x1, y1, x, y, flag(largeArc), flag(sweep), rx, ry, sin_phi, cos_phi)
+
+	pr := primitive{kind: primArc, seg: g.seg}
+	pr.p[0], pr.p[1] = g.current()
+	pr.p[2], pr.p[3] = g.apply(x, y)
+
+	// center and axes of the ellipse, transformed
+	a := &pr.arc
+	a.cx, a.cy = g.apply(cc[0], cc[1])
+	a.ux, a.uy = rx*cos_phi, rx*sin_phi
+	a.vx, a.vy = -ry*sin_phi, ry*cos_phi
+	if g.m != nil {
+		m := g.m
+		a.ux, a.uy = a.ux*m[0]+a.uy*m[2], a.ux*m[1]+a.uy*m[3]
+		a.vx, a.vy = a.vx*m[0]+a.vy*m[2], a.vx*m[1]+a.vy*m[3]
+	}
+	a.theta1, a.dtheta = cc[2], cc[3]
+
+	c := g.last()
+	c.prims = append(c.prims, pr)
+	g.x, g.y = x, y
+}
+
+func (g *geometryBuilder) Close() {
+	c := g.last()
+	x, y := g.current()
+	if x != c.startX || y != c.startY {
+		pr := primitive{kind: primLine, seg: g.seg, closing: true}
+		pr.p = [8]float64{x, y, c.startX, c.startY}
+		c.prims = append(c.prims, pr)
+	}
+	c.closed = true
+
+	// drawing after `z` starts a new subpath at the same point
+	g.contours = append(g.contours, contour{startX: c.startX, startY: c.startY})
+	g.x, g.y = g.startX, g.startY
+}
+
+// Current transform of the path: pending stack combined the same way
+// as in evaluateStack. Nil for identity.
+//
+func (sp *SvgPath) stackMatrix() []float64 {
+	if len(sp.stack) == 0 {
+		return nil
+	}
+	m := NewMatrix()
+	for i := len(sp.stack) - 1; i >= 0; i-- {
+		if len(sp.stack[i].queue) > 0 {
+			m.Matrix(sp.stack[i].ToArray())
+		}
+	}
+	if len(m.queue) == 0 {
+		return nil
+	}
+	return m.ToArray()
+}
+
+// Geometry of the path with pending transforms applied, segments are
+// not modified. Contours left empty after `z` are dropped.
+//
+func (sp *SvgPath) contours() []contour {
+	g := &geometryBuilder{m: sp.stackMatrix()}
+	r := newPathResolver(g)
+	for index, s := range sp.segments {
+		g.seg = index
+		if len(s.Command) != 1 {
+			continue
+		}
+		if len(g.contours) == 0 && s.Command != "M" && s.Command != "m" {
+			continue
+		}
+		r.segment(rune(s.Command[0]), s.Params)
+	}
+
+	// drop implicit contours after `z` with nothing drawn
+	res := g.contours[:0]
+	for i, c := range g.contours {
+		if i > 0 && g.contours[i-1].closed && len(c.prims) == 0 && !c.closed {
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// Absolute path segment drawing the primitive from its start point
+//
+func (pr *primitive) segment() *Segment {
+	p := &pr.p
+	switch pr.kind {
+	case primQuad:
+		return &Segment{Command: "Q", Params: []float64{p[2], p[3], p[4], p[5]}}
+	case primCubic:
+		return &Segment{Command: "C", Params: []float64{p[2], p[3], p[4], p[5], p[6], p[7]}}
+	case primArc:
+		// axes of the image of the unit circle under [u v]
+		a := &pr.arc
+		e := NewEllipse(1, 1, 0)
+		e.Transform([]float64{a.ux, a.uy, a.vx, a.vy, 0, 0})
+		if e.IsDegenerate() {
+			break
+		}
+
+		largeArc := math.Abs(a.dtheta) > math.Pi
+		// angles go from u towards v, mirrored when [u v] flips orientation
+		sweep := (a.dtheta > 0) != (a.ux*a.vy-a.uy*a.vx < 0)
+		return &Segment{Command: "A", Params: []float64{e.rx, e.ry, e.ax, flag(largeArc), flag(sweep), p[2], p[3]}}
+	}
+	return &Segment{Command: "L", Params: []float64{p[2], p[3]}}
+}
+
+// Part of the primitive between parameters t0 < t1
+//
+func (pr *primitive) sub(t0, t1 float64) primitive {
+	res := *pr
+	switch pr.kind {
+	case primLine:
+		res.p[0], res.p[1] = pr.at(t0)
+		res.p[2], res.p[3] = pr.at(t1)
+	case primArc:
+		res.p[0], res.p[1] = pr.at(t0)
+		res.p[2], res.p[3] = pr.at(t1)
+		res.arc.theta1 = pr.arc.theta1 + t0*pr.arc.dtheta
+		res.arc.dtheta = (t1 - t0) * pr.arc.dtheta
+	default:
+		// de Casteljau: cut the tail at t1, then the head of what is left
+		n := 6
+		if pr.kind == primCubic {
+			n = 8
+		}
+		if t1 < 1 {
+			splitBezier(res.p[:n], t1, true)
+		}
+		if t0 > 0 {
+			splitBezier(res.p[:n], t0/t1, false)
+		}
+	}
+	return res
+}
+
+// Replace Bézier control points p with the part before t (head) or
+// after t (tail). Head is made of the first points of de Casteljau
+// levels, tail of the last ones.
+//
+func splitBezier(p []float64, t float64, head bool) {
+	var tmp [8]float64
+	n := len(p) / 2
+	copy(tmp[:], p)
+	for level := 1; level < n; level++ {
+		for i := 0; i < n-level; i++ {
+			tmp[2*i] += t * (tmp[2*i+2] - tmp[2*i])
+			tmp[2*i+1] += t * (tmp[2*i+3] - tmp[2*i+1])
+		}
+		if head {
+			p[2*level], p[2*level+1] = tmp[0], tmp[1]
+		} else {
+			k := n - 1 - level
+			p[2*k], p[2*k+1] = tmp[2*k], tmp[2*k+1]
+		}
+	}
+}
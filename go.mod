@@ -1,9 +1,9 @@
 module github.com/propellerfactory/svgpath
 
-go 1.12
+go 1.13
 
 require (
-	github.com/pkg/errors v0.8.1
+	github.com/pkg/errors v0.9.1
 	github.com/propellerfactory/cubic2quad v0.0.0-20191009193713-f0bc352f4319
 	github.com/stretchr/testify v1.4.0
 )
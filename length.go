@@ -0,0 +1,176 @@
+package svgpath
+
+import (
+	"math"
+)
+
+// Absolute error of a segment length, used by Length, SegmentLengths
+// and SubpathLengths
+const DefaultLengthAccuracy = 1e-6
+
+// Gauss-Legendre nodes and weights on [-1, 1]
+var glNodes, glWeights = legendreNodes(16)
+
+// Compute n Gauss-Legendre nodes as roots of Legendre polynomial
+// by Newton iterations
+//
+func legendreNodes(n int) ([]float64, []float64) {
+	nodes := make([]float64, n)
+	weights := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(n) + 0.5))
+		var dp float64
+		for iter := 0; iter < 100; iter++ {
+			// p = P_n(x), dp = P_n'(x) by recurrence
+			p0, p1 := 1.0, x
+			for k := 2; k <= n; k++ {
+				p0, p1 = p1, ((2*float64(k)-1)*x*p1-(float64(k)-1)*p0)/float64(k)
+			}
+			dp = float64(n) * (x*p1 - p0) / (x*x - 1)
+			dx := p1 / dp
+			x -= dx
+			if math.Abs(dx) < 1e-16 {
+				break
+			}
+		}
+		nodes[i] = x
+		weights[i] = 2 / ((1 - x*x) * dp * dp)
+	}
+	return nodes, weights
+}
+
+// Integral of f over [a, b]
+//
+func gaussLegendre(f func(float64) float64, a, b float64) float64 {
+	half, mid := (b-a)/2, (a+b)/2
+	sum := 0.0
+	for i, x := range glNodes {
+		sum += glWeights[i] * f(mid+half*x)
+	}
+	return sum * half
+}
+
+// Integral of f over [a, b] with absolute error up to tol, whole is
+// the integral estimate on the full interval
+//
+func adaptiveGaussLegendre(f func(float64) float64, a, b, whole, tol float64, depth int) float64 {
+	mid := (a + b) / 2
+	left := gaussLegendre(f, a, mid)
+	right := gaussLegendre(f, mid, b)
+	if depth <= 0 || math.Abs(left+right-whole) <= tol {
+		return left + right
+	}
+	return adaptiveGaussLegendre(f, a, mid, left, tol/2, depth-1) +
+		adaptiveGaussLegendre(f, mid, b, right, tol/2, depth-1)
+}
+
+// Length of primitive part from t0 to t1
+//
+func (pr *primitive) lengthBetween(t0, t1, accuracy float64) float64 {
+	speed := func(t float64) float64 {
+		return math.Hypot(pr.deriv(t))
+	}
+	return adaptiveGaussLegendre(speed, t0, t1, gaussLegendre(speed, t0, t1), accuracy, 20)
+}
+
+func (pr *primitive) length(accuracy float64) float64 {
+	p := &pr.p
+	switch pr.kind {
+	case primLine:
+		return math.Hypot(p[2]-p[0], p[3]-p[1])
+	case primQuad:
+		if l, ok := quadLength(p[0], p[1], p[2], p[3], p[4], p[5]); ok {
+			return l
+		}
+	}
+	// cubics and arcs
+	return pr.lengthBetween(0, 1, accuracy)
+}
+
+// Closed form length of quadratic Bézier curve. Fails when the
+// formula is numerically unstable (control point on the chord line).
+//
+func quadLength(x0, y0, x1, y1, x2, y2 float64) (float64, bool) {
+	ax, ay := x0-2*x1+x2, y0-2*y1+y2
+	bx, by := 2*(x1-x0), 2*(y1-y0)
+
+	a := 4 * (ax*ax + ay*ay)
+	b := 4 * (ax*bx + ay*by)
+	c := bx*bx + by*by
+
+	if a < epsilon {
+		// control point in the middle, a straight line
+		return math.Hypot(x2-x0, y2-y0), true
+	}
+
+	sabc := 2 * math.Sqrt(a+b+c)
+	a2 := math.Sqrt(a)
+	a32 := 2 * a * a2
+	c2 := 2 * math.Sqrt(c)
+	ba := b / a2
+
+	arg := (2*a2 + ba + sabc) / (ba + c2)
+	if !(arg > 0) || math.IsInf(arg, 0) {
+		return 0, false
+	}
+
+	l := (a32*sabc + a2*b*(sabc-c2) + (4*c*a-b*b)*math.Log(arg)) / (4 * a32)
+	if math.IsNaN(l) || math.IsInf(l, 0) {
+		return 0, false
+	}
+	return l, true
+}
+
+// Total length of the path with pending transforms applied.
+//
+func (sp *SvgPath) Length() float64 {
+	return sp.LengthWithAccuracy(DefaultLengthAccuracy)
+}
+
+func (sp *SvgPath) LengthWithAccuracy(accuracy float64) float64 {
+	total := 0.0
+	for _, l := range sp.SubpathLengthsWithAccuracy(accuracy) {
+		total += l
+	}
+	return total
+}
+
+// Length of each segment, `Z` gets the length of the closing line.
+//
+func (sp *SvgPath) SegmentLengths() []float64 {
+	return sp.SegmentLengthsWithAccuracy(DefaultLengthAccuracy)
+}
+
+func (sp *SvgPath) SegmentLengthsWithAccuracy(accuracy float64) []float64 {
+	res := make([]float64, len(sp.segments))
+	if sp.err != nil {
+		return res
+	}
+	for _, c := range sp.contours() {
+		for i := range c.prims {
+			res[c.prims[i].seg] += c.prims[i].length(accuracy)
+		}
+	}
+	return res
+}
+
+// Length of each subpath. Drawing after `z` without a moveto starts
+// a new subpath.
+//
+func (sp *SvgPath) SubpathLengths() []float64 {
+	return sp.SubpathLengthsWithAccuracy(DefaultLengthAccuracy)
+}
+
+func (sp *SvgPath) SubpathLengthsWithAccuracy(accuracy float64) []float64 {
+	if sp.err != nil {
+		return []float64{}
+	}
+	contours := sp.contours()
+	res := make([]float64, len(contours))
+	for i, c := range contours {
+		for j := range c.prims {
+			res[i] += c.prims[j].length(accuracy)
+		}
+	}
+	return res
+}
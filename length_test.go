@@ -0,0 +1,77 @@
+package svgpath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Length of polyline through n+1 points of the path segment, for reference
+func sampledLength(pr *primitive, n int) float64 {
+	l := 0.0
+	px, py := pr.at(0)
+	for i := 1; i <= n; i++ {
+		x, y := pr.at(float64(i) / float64(n))
+		l += math.Hypot(x-px, y-py)
+		px, py = x, y
+	}
+	return l
+}
+
+func TestLengthLines(t *testing.T) {
+	sp := Parse("M0 0L3 4H10V0zM100 100l1 0")
+	assert.InDelta(t, 5+7+4+10+1, sp.Length(), 1e-12)
+	assert.Equal(t, []float64{0, 5, 7, 4, 10, 0, 1}, sp.SegmentLengths())
+	assert.Equal(t, []float64{26, 1}, sp.SubpathLengths())
+
+	assert.Equal(t, 0.0, Parse("").Length())
+	assert.Equal(t, 0.0, Parse("M0 0").Matrix([]float64{1}).Length())
+}
+
+func TestLengthCurves(t *testing.T) {
+	for _, path := range []string{
+		"M0 0Q50 100 100 0",
+		"M0 0Q100 0 50 0", // control point on the chord line, beyond the end
+		"M0 0C0 100 100 100 100 0",
+		"M0 0C100 100 0 100 100 0", // self-intersecting
+		"M0 0C50 0 50 0 100 0",
+	} {
+		sp := Parse(path)
+		pr := sp.contours()[0].prims[0]
+		assert.InDelta(t, sampledLength(&pr, 200000), sp.Length(), 1e-5, path)
+	}
+
+	// smooth and Catmull-Rom segments
+	sp := Parse("M0 0C0 10 10 10 10 0S20 -10 20 0R30 10 40 0")
+	lengths := sp.SegmentLengths()
+	assert.Equal(t, 4, len(lengths))
+	assert.InDelta(t, lengths[1], lengths[2], 1e-9, "mirrored curve")
+	assert.True(t, lengths[3] > 20)
+}
+
+func TestLengthArcs(t *testing.T) {
+	assert.InDelta(t, 2*math.Pi*10, Parse("M10 0A10 10 0 1 1 -10 0A10 10 0 1 1 10 0").Length(), 1e-9, "circle")
+	assert.InDelta(t, math.Pi*5, Parse("M0 0A1 1 0 0 1 10 0").Length(), 1e-9, "scaled radii")
+	assert.InDelta(t, 10, Parse("M0 0A0 10 0 0 1 10 0").Length(), 1e-12, "zero radius is a line")
+	assert.Equal(t, 0.0, Parse("M0 0A10 10 0 0 1 0 0").Length(), "omitted arc")
+
+	// ellipse rx=20, ry=10, compare with Ramanujan's approximation,
+	// accurate to ~1e-9 at this eccentricity
+	a, b := 20.0, 10.0
+	h := (a - b) * (a - b) / ((a + b) * (a + b))
+	expected := math.Pi * (a + b) * (1 + 3*h/(10+math.Sqrt(4-3*h)))
+	assert.InDelta(t, expected, Parse("M20 0A20 10 0 1 1 -20 0A20 10 0 1 1 20 0").Length(), 1e-5, "ellipse")
+}
+
+func TestLengthTransformed(t *testing.T) {
+	sp := Parse("M10 0A10 10 0 1 1 -10 0A10 10 0 1 1 10 0").Scale(2, 1).Rotate(30, 0, 0)
+	assert.InDelta(t, sp.Clone().Abs().Length(), sp.Length(), 1e-6)
+	assert.Equal(t, 2, len(sp.stack))
+}
+
+func TestLengthAccuracy(t *testing.T) {
+	sp := Parse("M0 0C300 0 -200 300 100 100")
+	precise := sp.LengthWithAccuracy(1e-12)
+	assert.InDelta(t, precise, sp.LengthWithAccuracy(1e-2), 1e-2)
+}
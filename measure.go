@@ -0,0 +1,424 @@
+package svgpath
+
+import (
+	"math"
+	"sort"
+)
+
+// PathMeasure answers queries about points of a path by the distance
+// along it, like getPointAtLength in browsers. Arc-length table is
+// computed once, queries take O(log n) time.
+//
+// Distances continue through all subpaths, gaps between subpaths
+// have zero length.
+type PathMeasure struct {
+	prims     []primitive
+	primFirst []int // first piece of each primitive, and len(pieces)
+	pieces    []measurePiece
+	nodes     []measureNode // bounding volume tree over pieces
+	contours  []measureContour
+	total     float64
+	accuracy  float64
+
+	// first point, for paths with nothing drawn
+	start Point
+}
+
+// Part of a primitive between t0 and t1, with its distances from the
+// path start
+type measurePiece struct {
+	prim   int
+	t0, t1 float64
+	d0, d1 float64
+	box    Rect
+}
+
+// Subpath: its start and range of primitives
+type measureContour struct {
+	start  Point
+	first  int
+	end    int
+	closed bool
+}
+
+type measureNode struct {
+	box         Rect
+	lo, hi      int // pieces range
+	left, right int // children, -1 for leaves
+}
+
+const (
+	// curves are split at least to 2^measureMinDepth pieces
+	measureMinDepth = 3
+	measureMaxDepth = 16
+
+	measureLeafSize = 4
+)
+
+func NewPathMeasure(sp *SvgPath) (*PathMeasure, error) {
+	return NewPathMeasureWithAccuracy(sp, DefaultLengthAccuracy)
+}
+
+// Build measure of path with pending transforms applied. Accuracy is
+// the absolute error of distances for each segment.
+//
+func NewPathMeasureWithAccuracy(sp *SvgPath, accuracy float64) (*PathMeasure, error) {
+	if sp.err != nil {
+		return nil, sp.err
+	}
+
+	pm := &PathMeasure{accuracy: accuracy}
+	contours := sp.contours()
+	if len(contours) > 0 {
+		pm.start = Point{contours[0].startX, contours[0].startY}
+	}
+	for _, c := range contours {
+		pm.contours = append(pm.contours, measureContour{
+			start:  Point{c.startX, c.startY},
+			first:  len(pm.prims),
+			end:    len(pm.prims) + len(c.prims),
+			closed: c.closed,
+		})
+		pm.prims = append(pm.prims, c.prims...)
+	}
+
+	for i := range pm.prims {
+		pm.primFirst = append(pm.primFirst, len(pm.pieces))
+		pr := &pm.prims[i]
+		if pr.kind == primLine {
+			pm.addPiece(i, 0, 1, pr.length(accuracy))
+			continue
+		}
+		pm.split(i, 0, 1, pr.speedIntegral(0, 1), 0)
+	}
+	pm.primFirst = append(pm.primFirst, len(pm.pieces))
+
+	if len(pm.pieces) > 0 {
+		pm.buildTree(0, len(pm.pieces))
+	}
+	return pm, nil
+}
+
+// Speed integral on [t0, t1] by a single Gauss-Legendre pass
+//
+func (pr *primitive) speedIntegral(t0, t1 float64) float64 {
+	return gaussLegendre(func(t float64) float64 {
+		return math.Hypot(pr.deriv(t))
+	}, t0, t1)
+}
+
+// Split primitive into pieces until their lengths are known with the
+// required accuracy
+//
+func (pm *PathMeasure) split(prim int, t0, t1, whole float64, depth int) {
+	pr := &pm.prims[prim]
+	mid := (t0 + t1) / 2
+	left := pr.speedIntegral(t0, mid)
+	right := pr.speedIntegral(mid, t1)
+
+	if depth >= measureMinDepth &&
+		(depth >= measureMaxDepth || math.Abs(left+right-whole) <= pm.accuracy) {
+		pm.addPiece(prim, t0, mid, left)
+		pm.addPiece(prim, mid, t1, right)
+		return
+	}
+	pm.split(prim, t0, mid, left, depth+1)
+	pm.split(prim, mid, t1, right, depth+1)
+}
+
+func (pm *PathMeasure) addPiece(prim int, t0, t1, length float64) {
+	sub := pm.prims[prim].sub(t0, t1)
+	b := bboxBuilder{empty: true}
+	b.primitive(&sub)
+
+	pm.pieces = append(pm.pieces, measurePiece{
+		prim: prim,
+		t0:   t0,
+		t1:   t1,
+		d0:   pm.total,
+		d1:   pm.total + length,
+		box:  b.r,
+	})
+	pm.total += length
+}
+
+// Build tree over pieces [lo, hi), return node index
+//
+func (pm *PathMeasure) buildTree(lo, hi int) int {
+	index := len(pm.nodes)
+	pm.nodes = append(pm.nodes, measureNode{lo: lo, hi: hi, left: -1, right: -1})
+
+	if hi-lo <= measureLeafSize {
+		box := pm.pieces[lo].box
+		for _, piece := range pm.pieces[lo+1 : hi] {
+			box = box.union(piece.box)
+		}
+		pm.nodes[index].box = box
+		return index
+	}
+
+	mid := (lo + hi) / 2
+	left := pm.buildTree(lo, mid)
+	right := pm.buildTree(mid, hi)
+	pm.nodes[index].left = left
+	pm.nodes[index].right = right
+	pm.nodes[index].box = pm.nodes[left].box.union(pm.nodes[right].box)
+	return index
+}
+
+func (r Rect) union(o Rect) Rect {
+	return Rect{
+		math.Min(r.MinX, o.MinX), math.Min(r.MinY, o.MinY),
+		math.Max(r.MaxX, o.MaxX), math.Max(r.MaxY, o.MaxY),
+	}
+}
+
+// Squared distance from point to rectangle, zero inside
+//
+func (r Rect) dist2(x, y float64) float64 {
+	dx := math.Max(0, math.Max(r.MinX-x, x-r.MaxX))
+	dy := math.Max(0, math.Max(r.MinY-y, y-r.MaxY))
+	return dx*dx + dy*dy
+}
+
+// Total length of the path
+//
+func (pm *PathMeasure) Length() float64 {
+	return pm.total
+}
+
+// Find primitive and its parameter at distance, clamped to the path
+// length. Returns false when nothing is drawn.
+//
+func (pm *PathMeasure) locate(distance float64) (*primitive, float64, bool) {
+	if len(pm.pieces) == 0 {
+		return nil, 0, false
+	}
+
+	distance = math.Max(0, math.Min(distance, pm.total))
+	i := sort.Search(len(pm.pieces), func(i int) bool {
+		return pm.pieces[i].d1 >= distance
+	})
+	if i == len(pm.pieces) {
+		i--
+	}
+	return &pm.prims[pm.pieces[i].prim], pm.pieceParam(i, distance), true
+}
+
+// Parameter of primitive at distance within its range
+//
+func (pm *PathMeasure) primParam(prim int, distance float64) float64 {
+	first, end := pm.primFirst[prim], pm.primFirst[prim+1]
+	i := first + sort.Search(end-first, func(i int) bool {
+		return pm.pieces[first+i].d1 >= distance
+	})
+	if i == end {
+		i--
+	}
+	return pm.pieceParam(i, distance)
+}
+
+// Parameter of piece primitive at distance within the piece
+//
+func (pm *PathMeasure) pieceParam(i int, distance float64) float64 {
+	piece := &pm.pieces[i]
+	pr := &pm.prims[piece.prim]
+
+	local := distance - piece.d0
+	length := piece.d1 - piece.d0
+	if length <= 0 || local <= 0 {
+		return piece.t0
+	}
+	if local >= length {
+		return piece.t1
+	}
+
+	t := piece.t0 + (piece.t1-piece.t0)*local/length
+	if pr.kind == primLine {
+		return t
+	}
+
+	// refine by Newton iterations on the piece length
+	for iter := 0; iter < 4; iter++ {
+		speed := math.Hypot(pr.deriv(t))
+		if speed < epsilon {
+			break
+		}
+		t -= (pr.speedIntegral(piece.t0, t) - local) / speed
+		t = math.Max(piece.t0, math.Min(t, piece.t1))
+	}
+	return t
+}
+
+// Distances of primitive start and end
+//
+func (pm *PathMeasure) primRange(prim int) (float64, float64) {
+	return pm.pieces[pm.primFirst[prim]].d0, pm.pieces[pm.primFirst[prim+1]-1].d1
+}
+
+// Point at distance along the path
+//
+func (pm *PathMeasure) PointAt(distance float64) Point {
+	pr, t, ok := pm.locate(distance)
+	if !ok {
+		return pm.start
+	}
+	x, y := pr.at(t)
+	return Point{x, y}
+}
+
+// Unit tangent vector at distance, in the direction of drawing
+//
+func (pm *PathMeasure) TangentAt(distance float64) Point {
+	pr, t, ok := pm.locate(distance)
+	if !ok {
+		return Point{1, 0}
+	}
+	return pr.tangent(t)
+}
+
+// Unit tangent of primitive at t. Where the derivative vanishes (curves
+// with control points on their ends), the direction is taken from
+// nearby points.
+//
+func (pr *primitive) tangent(t float64) Point {
+	dx, dy := pr.deriv(t)
+	if l := math.Hypot(dx, dy); l > epsilon {
+		return Point{dx / l, dy / l}
+	}
+
+	const dt = 1e-6
+	x0, y0 := pr.at(math.Max(0, t-dt))
+	x1, y1 := pr.at(math.Min(1, t+dt))
+	if l := math.Hypot(x1-x0, y1-y0); l > 0 {
+		return Point{(x1 - x0) / l, (y1 - y0) / l}
+	}
+
+	// degenerate primitive, use the chord
+	x0, y0 = pr.start()
+	x1, y1 = pr.end()
+	if l := math.Hypot(x1-x0, y1-y0); l > 0 {
+		return Point{(x1 - x0) / l, (y1 - y0) / l}
+	}
+	return Point{1, 0}
+}
+
+// Unit normal at distance: tangent rotated by 90 degrees, from x axis
+// towards y axis (to the right of the drawing direction in SVG
+// coordinates, where y goes down)
+//
+func (pm *PathMeasure) NormalAt(distance float64) Point {
+	tangent := pm.TangentAt(distance)
+	return Point{-tangent.Y, tangent.X}
+}
+
+// Tangent direction at distance, in degrees
+//
+func (pm *PathMeasure) AngleAt(distance float64) float64 {
+	tangent := pm.TangentAt(distance)
+	return math.Atan2(tangent.Y, tangent.X) / torad
+}
+
+// Find the path point nearest to (x, y). Returns its distance along
+// the path and the point itself.
+//
+func (pm *PathMeasure) Nearest(x, y float64) (float64, Point) {
+	if len(pm.pieces) == 0 {
+		return 0, pm.start
+	}
+
+	best := math.Inf(1)
+	bestPiece, bestT := 0, 0.0
+
+	var visit func(n int)
+	visit = func(n int) {
+		node := &pm.nodes[n]
+		if node.box.dist2(x, y) >= best {
+			return
+		}
+		if node.left < 0 {
+			for i := node.lo; i < node.hi; i++ {
+				if pm.pieces[i].box.dist2(x, y) >= best {
+					continue
+				}
+				t, d2 := pm.nearestInPiece(i, x, y)
+				if d2 < best {
+					best, bestPiece, bestT = d2, i, t
+				}
+			}
+			return
+		}
+		// closer child first, to prune more of the other one
+		left, right := node.left, node.right
+		if pm.nodes[right].box.dist2(x, y) < pm.nodes[left].box.dist2(x, y) {
+			left, right = right, left
+		}
+		visit(left)
+		visit(right)
+	}
+	visit(0)
+
+	piece := &pm.pieces[bestPiece]
+	pr := &pm.prims[piece.prim]
+	px, py := pr.at(bestT)
+
+	distance := piece.d0
+	if pr.kind == primLine {
+		distance += (piece.d1 - piece.d0) * (bestT - piece.t0) / (piece.t1 - piece.t0)
+	} else {
+		distance += pr.speedIntegral(piece.t0, bestT)
+	}
+	return distance, Point{px, py}
+}
+
+// Parameter of the piece point nearest to (x, y) and squared distance
+// to it
+//
+func (pm *PathMeasure) nearestInPiece(i int, x, y float64) (float64, float64) {
+	piece := &pm.pieces[i]
+	pr := &pm.prims[piece.prim]
+
+	dist2 := func(t float64) float64 {
+		px, py := pr.at(t)
+		return (px-x)*(px-x) + (py-y)*(py-y)
+	}
+
+	if pr.kind == primLine {
+		x0, y0 := pr.start()
+		x1, y1 := pr.end()
+		dx, dy := x1-x0, y1-y0
+		t := 0.0
+		if l2 := dx*dx + dy*dy; l2 > 0 {
+			t = math.Max(0, math.Min(1, ((x-x0)*dx+(y-y0)*dy)/l2))
+		}
+		return t, dist2(t)
+	}
+
+	// coarse sampling, then golden section search around the best sample
+	const samples = 8
+	step := (piece.t1 - piece.t0) / samples
+	bestT, best := piece.t0, dist2(piece.t0)
+	for k := 1; k <= samples; k++ {
+		t := piece.t0 + step*float64(k)
+		if d := dist2(t); d < best {
+			bestT, best = t, d
+		}
+	}
+
+	const phi = 0.6180339887498949
+	lo := math.Max(piece.t0, bestT-step)
+	hi := math.Min(piece.t1, bestT+step)
+	for iter := 0; iter < 60 && hi-lo > 1e-15; iter++ {
+		a := hi - phi*(hi-lo)
+		b := lo + phi*(hi-lo)
+		if dist2(a) < dist2(b) {
+			hi = b
+		} else {
+			lo = a
+		}
+	}
+	if t := (lo + hi) / 2; dist2(t) < best {
+		bestT, best = t, dist2(t)
+	}
+	return bestT, best
+}
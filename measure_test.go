@@ -0,0 +1,116 @@
+package svgpath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertPoint(t *testing.T, expected, actual Point, delta float64, msg string) {
+	assert.InDelta(t, expected.X, actual.X, delta, msg)
+	assert.InDelta(t, expected.Y, actual.Y, delta, msg)
+}
+
+func TestMeasureLines(t *testing.T) {
+	pm, err := NewPathMeasure(Parse("M0 0H10V10M20 20h5"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 25, pm.Length(), 1e-12)
+
+	assertPoint(t, Point{5, 0}, pm.PointAt(5), 1e-12, "first line")
+	assertPoint(t, Point{10, 5}, pm.PointAt(15), 1e-12, "second line")
+	assertPoint(t, Point{22, 20}, pm.PointAt(22), 1e-12, "second subpath")
+	assertPoint(t, Point{0, 0}, pm.PointAt(-1), 1e-12, "clamped start")
+	assertPoint(t, Point{25, 20}, pm.PointAt(100), 1e-12, "clamped end")
+
+	assertPoint(t, Point{1, 0}, pm.TangentAt(5), 1e-12, "tangent")
+	assertPoint(t, Point{0, 1}, pm.TangentAt(15), 1e-12, "tangent")
+	assertPoint(t, Point{0, 1}, pm.NormalAt(5), 1e-12, "normal")
+	assert.InDelta(t, 90, pm.AngleAt(15), 1e-12)
+}
+
+func TestMeasureCircle(t *testing.T) {
+	// clockwise in SVG coordinates, starting at (10, 0)
+	pm, err := NewPathMeasure(Parse("M10 0A10 10 0 1 1 -10 0A10 10 0 1 1 10 0"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 20*math.Pi, pm.Length(), 1e-9)
+
+	for _, angle := range []float64{0, 0.3, 1, 2, 3, 4, 5, 6} {
+		p := pm.PointAt(angle * 10)
+		assertPoint(t, Point{10 * math.Cos(angle), 10 * math.Sin(angle)}, p, 1e-6, "point")
+		assertPoint(t, Point{-math.Sin(angle), math.Cos(angle)}, pm.TangentAt(angle*10), 1e-6, "tangent")
+		assertPoint(t, Point{-math.Cos(angle), -math.Sin(angle)}, pm.NormalAt(angle*10), 1e-6, "normal points inside")
+	}
+}
+
+func TestMeasureCubic(t *testing.T) {
+	sp := Parse("M0 0C0 100 100 100 100 0")
+	pm, err := NewPathMeasure(sp)
+	assert.Nil(t, err)
+	assert.InDelta(t, sp.Length(), pm.Length(), 1e-6)
+
+	// symmetric curve: the middle is at half the length
+	assertPoint(t, Point{50, 75}, pm.PointAt(pm.Length()/2), 1e-6, "middle")
+	assert.InDelta(t, 0, pm.AngleAt(pm.Length()/2), 1e-6)
+
+	// distance of a point is the length of the curve part before it
+	for _, d := range []float64{0, 10, 55.5, 100, 150, pm.Length()} {
+		p := pm.PointAt(d)
+		distance, nearest := pm.Nearest(p.X, p.Y)
+		assert.InDelta(t, d, distance, 1e-5)
+		assertPoint(t, p, nearest, 1e-6, "nearest")
+	}
+
+	// degenerate control points: tangent is still defined at the ends
+	pm, _ = NewPathMeasure(Parse("M0 0C0 0 10 10 10 10"))
+	s := math.Sqrt(0.5)
+	assertPoint(t, Point{s, s}, pm.TangentAt(0), 1e-6, "start")
+	assertPoint(t, Point{s, s}, pm.TangentAt(pm.Length()), 1e-6, "end")
+}
+
+func TestMeasureNearest(t *testing.T) {
+	pm, _ := NewPathMeasure(Parse("M0 0H100V100H0z"))
+	distance, p := pm.Nearest(50, -10)
+	assert.InDelta(t, 50, distance, 1e-9)
+	assertPoint(t, Point{50, 0}, p, 1e-9, "top")
+
+	distance, p = pm.Nearest(-5, 60)
+	assert.InDelta(t, 340, distance, 1e-9)
+	assertPoint(t, Point{0, 60}, p, 1e-9, "left, closing line")
+
+	// brute force check on a wavy path
+	sp := Parse("M0 0Q25 50 50 0T100 0T150 0A25 40 0 0 1 200 0C250 100 150 100 200 -50")
+	pm, _ = NewPathMeasure(sp)
+	for _, q := range []Point{{10, 10}, {100, 30}, {175, -20}, {210, 40}, {-50, 500}} {
+		_, p := pm.Nearest(q.X, q.Y)
+		got := math.Hypot(p.X-q.X, p.Y-q.Y)
+
+		best := math.Inf(1)
+		for i := 0; i <= 100000; i++ {
+			c := pm.PointAt(pm.Length() * float64(i) / 100000)
+			best = math.Min(best, math.Hypot(c.X-q.X, c.Y-q.Y))
+		}
+		assert.InDelta(t, best, got, 1e-3, "%v", q)
+		assert.True(t, got <= best+1e-9, "%v", q)
+	}
+}
+
+func TestMeasureEmpty(t *testing.T) {
+	pm, err := NewPathMeasure(Parse("M5 5"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, pm.Length())
+	assert.Equal(t, Point{5, 5}, pm.PointAt(1))
+	distance, p := pm.Nearest(0, 0)
+	assert.Equal(t, 0.0, distance)
+	assert.Equal(t, Point{5, 5}, p)
+
+	_, err = NewPathMeasure(Parse("M0 0").Matrix([]float64{1}))
+	assert.NotNil(t, err)
+}
+
+func TestMeasureTransformed(t *testing.T) {
+	sp := Parse("M0 0L10 0").Rotate(90, 0, 0)
+	pm, _ := NewPathMeasure(sp)
+	assertPoint(t, Point{0, 5}, pm.PointAt(5), 1e-12, "rotated")
+	assert.Equal(t, 1, len(sp.stack))
+}
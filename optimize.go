@@ -0,0 +1,160 @@
+package svgpath
+
+import (
+	"strings"
+)
+
+// Convert path to string, choosing for each segment whichever of the
+// absolute and relative forms is shorter at opts.Precision. Lines
+// are also written as `H`/`V` when possible.
+//
+// Relative coordinates are taken from the rounded position a reader of
+// the result will arrive at, not from the exact one, so rounding errors
+// don't accumulate when switching between forms.
+//
+func (sp *SvgPath) ToStringOptimized(opts FormatOptions) string {
+	sp.evaluateStack()
+
+	abs := sp.Clone()
+	abs.Abs()
+
+	o := pathOptimizer{f: pathFormatter{opts: opts}}
+	for index, s := range abs.segments {
+		o.segment(index, s)
+	}
+	return string(o.f.buf)
+}
+
+type pathOptimizer struct {
+	f pathFormatter
+
+	// exact current point and subpath start
+	x, y           float64
+	startX, startY float64
+
+	// the same points, as seen by a reader of the output
+	qx, qy           float64
+	qStartX, qStartY float64
+
+	// reused candidate params
+	abs, rel []float64
+
+	// buffers of trial formatters, so that measuring never writes
+	// into the output
+	trialBuf, trialScratch []byte
+}
+
+func (o *pathOptimizer) round(v float64) float64 {
+	digits := o.f.opts.digits()
+	if digits < 0 {
+		return v
+	}
+	return toFixed(v, digits)
+}
+
+// Length of output for a candidate segment
+//
+func (o *pathOptimizer) measure(cmd string, params []float64) int {
+	trial := o.f
+	trial.buf = o.trialBuf[:0]
+	trial.scratch = o.trialScratch[:0]
+	trial.segment(cmd, params)
+	o.trialBuf, o.trialScratch = trial.buf, trial.scratch
+	return len(trial.buf)
+}
+
+func (o *pathOptimizer) segment(index int, s *Segment) {
+	cmd := s.Command
+	p := s.Params
+
+	// candidates: absolute and relative params, command letters
+	o.abs = o.abs[:0]
+	o.rel = o.rel[:0]
+	absCmd := cmd
+	relCmd := strings.ToLower(cmd)
+
+	switch cmd {
+	case "Z":
+		o.f.segment(cmd, p)
+		o.x, o.y = o.startX, o.startY
+		o.qx, o.qy = o.qStartX, o.qStartY
+		return
+
+	case "H", "V", "L":
+		ex, ey := o.x, o.y
+		switch cmd {
+		case "H":
+			ex = p[0]
+		case "V":
+			ey = p[0]
+		default:
+			ex, ey = p[0], p[1]
+		}
+		o.line(ex, ey)
+		return
+
+	case "A":
+		// rx, ry, x-axis-rotation (with better precision), flags
+		rotation := p[2]
+		if digits := o.f.opts.digits(); digits >= 0 {
+			rotation = toFixed(p[2], digits+2)
+		}
+		o.abs = append(o.abs, o.round(p[0]), o.round(p[1]), rotation, p[3], p[4])
+		o.rel = append(o.rel, o.abs...)
+		o.abs = append(o.abs, o.round(p[5]), o.round(p[6]))
+		o.rel = append(o.rel, o.round(p[5]-o.qx), o.round(p[6]-o.qy))
+
+	default:
+		// M C S Q T R - points only
+		for i := 0; i < len(p); i += 2 {
+			o.abs = append(o.abs, o.round(p[i]), o.round(p[i+1]))
+			o.rel = append(o.rel, o.round(p[i]-o.qx), o.round(p[i+1]-o.qy))
+		}
+	}
+
+	// the very first moveto is always absolute
+	if index == 0 || o.measure(absCmd, o.abs) < o.measure(relCmd, o.rel) {
+		o.f.segment(absCmd, o.abs)
+	} else {
+		o.f.segment(relCmd, o.rel)
+	}
+
+	l := len(p)
+	o.x, o.y = p[l-2], p[l-1]
+	o.qx, o.qy = o.abs[len(o.abs)-2], o.abs[len(o.abs)-1]
+
+	if cmd == "M" {
+		o.startX, o.startY = o.x, o.y
+		o.qStartX, o.qStartY = o.qx, o.qy
+	}
+}
+
+// Write line to exact (ex, ey) in the shortest of L, l, H, h, V, v
+//
+func (o *pathOptimizer) line(ex, ey float64) {
+	qx, qy := o.round(ex), o.round(ey)
+
+	var best []float64
+	bestCmd := ""
+	bestLen := 0
+	try := func(cmd string, params ...float64) {
+		if l := o.measure(cmd, params); bestCmd == "" || l < bestLen {
+			best, bestCmd, bestLen = params, cmd, l
+		}
+	}
+
+	if qy == o.qy {
+		try("h", o.round(ex-o.qx))
+		try("H", qx)
+	}
+	if qx == o.qx {
+		try("v", o.round(ey-o.qy))
+		try("V", qy)
+	}
+	try("l", o.round(ex-o.qx), o.round(ey-o.qy))
+	try("L", qx, qy)
+
+	o.f.segment(bestCmd, best)
+	o.x, o.y = ex, ey
+	o.qx, o.qy = qx, qy
+}
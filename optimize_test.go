@@ -0,0 +1,54 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimizeChoosesShorterForm(t *testing.T) {
+	sp, err := NewSvgPath("M100 100L101 101L200 100L200 50C200 60 210 60 210 50L10 10Z")
+	assert.Nil(t, err)
+	assert.Equal(t, "M100 100l1 1 99-1V50c0 10 10 10 10 0L10 10Z",
+		sp.ToStringOptimized(MinifyFormatOptions(2)))
+}
+
+func TestOptimizeKeepsFirstMoveAbsolute(t *testing.T) {
+	sp, err := NewSvgPath("m 1000 1000 l 1 1 m 1 1 z")
+	assert.Nil(t, err)
+	assert.Equal(t, "M1000 1000l1 1m1 1Z", sp.ToStringOptimized(MinifyFormatOptions(2)))
+}
+
+func TestOptimizeNoErrorAccumulation(t *testing.T) {
+	// many small relative steps, each rounds down on its own
+	sp, err := NewSvgPath("M0 0l0.4 0l0.4 0l0.4 0l0.4 0l0.4 0l0.4 0L100 0")
+	assert.Nil(t, err)
+	out := sp.ToStringOptimized(MinifyFormatOptions(0))
+	assert.Equal(t, "M0 0h0 1 0 1 0 0 98", out)
+
+	// positions read back are the rounded exact ones: 0.4 0.8 1.2 ...
+	back, err := NewSvgPath(out)
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0H0 1 1 2 2 2 100", back.Abs().ToString())
+}
+
+func TestOptimizeHV(t *testing.T) {
+	sp, err := NewSvgPath("M10 10L50 10L50 20L10 20")
+	assert.Nil(t, err)
+	assert.Equal(t, "M10 10h40v10H10", sp.ToStringOptimized(MinifyFormatOptions(2)))
+}
+
+func TestOptimizeMeasureKeepsOutput(t *testing.T) {
+	o := pathOptimizer{f: pathFormatter{opts: DefaultFormatOptions}}
+	o.f.buf = append(make([]byte, 0, 64), "M1 1"...)
+	spare := o.f.buf[len(o.f.buf):cap(o.f.buf)]
+	for i := range spare {
+		spare[i] = '#'
+	}
+
+	assert.Equal(t, len("L10 10"), o.measure("L", []float64{10, 10}))
+	assert.Equal(t, "M1 1", string(o.f.buf))
+	for _, b := range spare {
+		assert.Equal(t, byte('#'), b, "output buffer is not written")
+	}
+}
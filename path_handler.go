@@ -0,0 +1,140 @@
+package svgpath
+
+// PathHandler receives path segments in absolute coordinates.
+//
+// Shorthand and relative commands are resolved before they reach the
+// handler: `H`/`V` become LineTo, `S`/`T` get their reflected control
+// point, and Catmull-Rom `R` is reported as a sequence of CubicTo.
+type PathHandler interface {
+	MoveTo(x, y float64)
+	LineTo(x, y float64)
+	QuadTo(x1, y1, x, y float64)
+	CubicTo(x1, y1, x2, y2, x, y float64)
+	ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64)
+	Close()
+}
+
+// pathResolver tracks the pen state needed to turn raw segments into
+// absolute PathHandler calls
+type pathResolver struct {
+	h PathHandler
+
+	// current point and start of the current subpath
+	x, y           float64
+	startX, startY float64
+
+	// last control point of the previous curve, for `S` and `T`
+	ctrlX, ctrlY float64
+
+	// previous command, in upper case
+	prev rune
+}
+
+func newPathResolver(h PathHandler) *pathResolver {
+	return &pathResolver{h: h}
+}
+
+// Resolve one segment. `cmd` is a path command letter, params have
+// the layout of the command (see Command.ParamCount).
+//
+func (r *pathResolver) segment(cmd rune, p []float64) {
+	// relative coordinates are shifted by the current point
+	var dx, dy float64
+	if cmd|0x20 == cmd {
+		dx, dy = r.x, r.y
+	}
+
+	switch cmd | 0x20 {
+	case 0x6D /* m */ :
+		r.x, r.y = p[0]+dx, p[1]+dy
+		r.startX, r.startY = r.x, r.y
+		r.h.MoveTo(r.x, r.y)
+
+	case 0x6C /* l */ :
+		r.x, r.y = p[0]+dx, p[1]+dy
+		r.h.LineTo(r.x, r.y)
+
+	case 0x68 /* h */ :
+		r.x = p[0] + dx
+		r.h.LineTo(r.x, r.y)
+
+	case 0x76 /* v */ :
+		r.y = p[0] + dy
+		r.h.LineTo(r.x, r.y)
+
+	case 0x63 /* c */ :
+		r.cubic(p[0]+dx, p[1]+dy, p[2]+dx, p[3]+dy, p[4]+dx, p[5]+dy)
+
+	case 0x73 /* s */ :
+		x1, y1 := r.x, r.y
+		if r.prev == 0x43 /* C */ || r.prev == 0x53 /* S */ || r.prev == 0x52 /* R */ {
+			x1, y1 = 2*r.x-r.ctrlX, 2*r.y-r.ctrlY
+		}
+		r.cubic(x1, y1, p[0]+dx, p[1]+dy, p[2]+dx, p[3]+dy)
+
+	case 0x71 /* q */ :
+		r.quad(p[0]+dx, p[1]+dy, p[2]+dx, p[3]+dy)
+
+	case 0x74 /* t */ :
+		x1, y1 := r.x, r.y
+		if r.prev == 0x51 /* Q */ || r.prev == 0x54 /* T */ {
+			x1, y1 = 2*r.x-r.ctrlX, 2*r.y-r.ctrlY
+		}
+		r.quad(x1, y1, p[0]+dx, p[1]+dy)
+
+	case 0x61 /* a */ :
+		// ARC is: ['A', rx, ry, x-axis-rotation, large-arc-flag, sweep-flag, x, y]
+		r.x, r.y = p[5]+dx, p[6]+dy
+		r.h.ArcTo(p[0], p[1], p[2], p[3] != 0, p[4] != 0, r.x, r.y)
+
+	case 0x72 /* r */ :
+		r.catmullRom(p, dx, dy)
+
+	case 0x7A /* z */ :
+		r.x, r.y = r.startX, r.startY
+		r.h.Close()
+	}
+
+	r.prev = cmd &^ 0x20
+}
+
+func (r *pathResolver) cubic(x1, y1, x2, y2, x, y float64) {
+	r.ctrlX, r.ctrlY = x2, y2
+	r.x, r.y = x, y
+	r.h.CubicTo(x1, y1, x2, y2, x, y)
+}
+
+func (r *pathResolver) quad(x1, y1, x, y float64) {
+	r.ctrlX, r.ctrlY = x1, y1
+	r.x, r.y = x, y
+	r.h.QuadTo(x1, y1, x, y)
+}
+
+// Same math as catmullRom2c, without building the point list
+//
+func (r *pathResolver) catmullRom(p []float64, dx, dy float64) {
+	n := len(p)/2 + 1
+	x0, y0 := r.x, r.y
+
+	// k-th point of the curve, the current point is the 0-th
+	point := func(k int) (float64, float64) {
+		if k <= 0 {
+			return x0, y0
+		}
+		if k >= n {
+			k = n - 1
+		}
+		return p[2*k-2] + dx, p[2*k-1] + dy
+	}
+
+	for k := 0; k+1 < n; k++ {
+		ax, ay := point(k - 1)
+		bx, by := point(k)
+		cx, cy := point(k + 1)
+		ex, ey := point(k + 2)
+		r.cubic(
+			(-ax+6*bx+cx)/6, (-ay+6*by+cy)/6,
+			(bx+6*cx-ex)/6, (by+6*cy-ey)/6,
+			cx, cy)
+	}
+}
@@ -0,0 +1,128 @@
+package svgpath
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingHandler struct {
+	calls []string
+}
+
+func (h *recordingHandler) MoveTo(x, y float64) {
+	h.calls = append(h.calls, fmt.Sprintf("M%g,%g", x, y))
+}
+
+func (h *recordingHandler) LineTo(x, y float64) {
+	h.calls = append(h.calls, fmt.Sprintf("L%g,%g", x, y))
+}
+
+func (h *recordingHandler) QuadTo(x1, y1, x, y float64) {
+	h.calls = append(h.calls, fmt.Sprintf("Q%g,%g %g,%g", x1, y1, x, y))
+}
+
+func (h *recordingHandler) CubicTo(x1, y1, x2, y2, x, y float64) {
+	h.calls = append(h.calls, fmt.Sprintf("C%g,%g %g,%g %g,%g", x1, y1, x2, y2, x, y))
+}
+
+func (h *recordingHandler) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) {
+	h.calls = append(h.calls, fmt.Sprintf("A%g,%g %g %t %t %g,%g", rx, ry, rotation, largeArc, sweep, x, y))
+}
+
+func (h *recordingHandler) Close() {
+	h.calls = append(h.calls, "Z")
+}
+
+type nopHandler struct{}
+
+func (nopHandler) MoveTo(x, y float64)                                                {}
+func (nopHandler) LineTo(x, y float64)                                                {}
+func (nopHandler) QuadTo(x1, y1, x, y float64)                                        {}
+func (nopHandler) CubicTo(x1, y1, x2, y2, x, y float64)                               {}
+func (nopHandler) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) {}
+func (nopHandler) Close()                                                             {}
+
+func TestHandlerLines(t *testing.T) {
+	h := &recordingHandler{}
+	err := PathParseTo("m10 10 20 0 h10 v10 H0 V5 l-5-5 z l1 1 M1 2Z", h)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"M10,10", "L30,10", "L40,10", "L40,20", "L0,20", "L0,5", "L-5,0", "Z",
+		"L11,11", "M1,2", "Z",
+	}, h.calls)
+}
+
+func TestHandlerCurves(t *testing.T) {
+	h := &recordingHandler{}
+	err := PathParseTo("M10 10 C 20 20, 40 20, 50 10 S 80 0, 90 10 s 10 10 20 0", h)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"M10,10", "C20,20 40,20 50,10", "C60,0 80,0 90,10", "C100,20 100,20 110,10",
+	}, h.calls, "should reflect cubic control points")
+
+	h = &recordingHandler{}
+	err = PathParseTo("M30 50 q 20 20, 40 0 t 40 0 T150 50 L0 0 T10 10", h)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"M30,50", "Q50,70 70,50", "Q90,30 110,50", "Q130,70 150,50", "L0,0", "Q0,0 10,10",
+	}, h.calls, "should reflect quadratic control points")
+
+	h = &recordingHandler{}
+	err = PathParseTo("M10 10 S 50 50, 90 10", h)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"M10,10", "C10,10 50,50 90,10"}, h.calls, "should use current point without previous curve")
+}
+
+func TestHandlerArcsAndCatmullRom(t *testing.T) {
+	h := &recordingHandler{}
+	err := PathParseTo("M40 30a20 40 -45 0 1 20 50A1 2 3 1 0 5 6", h)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"M40,30", "A20,40 -45 false true 60,80", "A1,2 3 true false 5,6"}, h.calls)
+
+	// compare with Uncatmull
+	sp, err := NewSvgPath("M0 0 R10 0 20 10 30 0")
+	assert.Nil(t, err)
+	sp.Uncatmull()
+	expected := &recordingHandler{}
+	assert.Nil(t, PathParseTo(sp.ToString(), expected))
+
+	h = &recordingHandler{}
+	assert.Nil(t, PathParseTo("M0 0 R10 0 20 10 30 0", h))
+	assert.Equal(t, expected.calls, h.calls)
+
+	h = &recordingHandler{}
+	assert.Nil(t, PathParseTo("M0 0 r10 0 20 10 30 0", h))
+	assert.Equal(t, expected.calls, h.calls)
+}
+
+func TestHandlerError(t *testing.T) {
+	h := &recordingHandler{}
+	err := PathParseReaderTo(strings.NewReader("M0 0L10 10 20"), h)
+	assert.Equal(t, "SvgPath: missed param (at pos 13)", err.Error())
+	assert.Equal(t, []string{"M0,0", "L10,10"}, h.calls)
+}
+
+func TestHandlerAllocations(t *testing.T) {
+	var short, long bytes.Buffer
+	short.WriteString("M0 0")
+	long.WriteString("M0 0")
+	for i := 0; i < 1000; i++ {
+		if i < 10 {
+			short.WriteString("l1.5-2.5c1 2 3 4 5 6s1 2 3 4a1 1 0 0 1 2 2h1v1z")
+		}
+		long.WriteString("l1.5-2.5c1 2 3 4 5 6s1 2 3 4a1 1 0 0 1 2 2h1v1z")
+	}
+
+	allocs := func(b []byte) float64 {
+		return testing.AllocsPerRun(10, func() {
+			if err := PathParseBytesTo(b, nopHandler{}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	assert.Equal(t, allocs(short.Bytes()), allocs(long.Bytes()), "should not allocate per segment")
+}
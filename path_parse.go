@@ -2,7 +2,10 @@
 package svgpath
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -10,8 +13,6 @@ import (
 	"github.com/pkg/errors"
 )
 
-var paramCounts = map[string]int{"a": 7, "c": 6, "h": 1, "l": 2, "m": 2, "r": 4, "q": 4, "s": 4, "t": 2, "v": 1, "z": 0}
-
 var SPECIAL_SPACES = []rune{
 	0x1680, 0x180E, 0x2000, 0x2001, 0x2002, 0x2003, 0x2004, 0x2005, 0x2006,
 	0x2007, 0x2008, 0x2009, 0x200A, 0x202F, 0x205F, 0x3000, 0xFEFF,
@@ -73,215 +74,334 @@ func isDigitStart(code rune) bool {
 		code == 0x2E /* . */
 }
 
+// eof is the value of State.ch once the input is exhausted
+const eof = -1
+
+// Number of bytes kept around the error position for ParseError.Snippet
+const snippetContext = 40
+
+// ParseError describes malformed path data. Use errors.As to get it from
+// the error returned by PathParse and friends.
+type ParseError struct {
+	Offset   int    // byte offset of the offending input
+	Line     int    // 1-based line number
+	Column   int    // 1-based column, in runes
+	Token    string // offending text, empty at the end of input
+	Expected string // what the parser was looking for
+	Snippet  string // source text around the error, on the same line
+
+	// Segments successfully parsed before the error
+	Segments []*Segment
+
+	msg string
+}
+
+func (e *ParseError) Error() string {
+	return "SvgPath: " + e.msg
+}
+
+// State is a single pass lexer over path data. It keeps one rune of
+// lookahead, so parsing is linear in the size of the input and the
+// source does not have to be held in memory as a whole.
 type State struct {
-	index        int
-	path         string
-	max          int
+	src          io.RuneReader
+	ch           rune // current rune, eof at the end of input
+	width        int  // byte width of ch
+	index        int  // byte offset of ch
 	result       []*Segment
 	param        float64
 	err          error
+	readErr      error
+	cmd          rune
 	segmentStart int
 	data         []float64
+	buf          []byte // text of the number being scanned
+	line         int    // line of ch
+	column       int    // column of ch
+	window       []byte // current line up to and including ch, trimmed to snippetContext
+	handler      *pathResolver
+	count        int // number of segments scanned
 }
 
 func NewState(path string) *State {
-	return &State{
-		index:        0,
-		path:         path,
-		max:          utf8.RuneCountInString(path),
-		result:       []*Segment{},
-		param:        0.0,
-		err:          nil,
-		segmentStart: 0,
-		data:         []float64{},
+	return newState(strings.NewReader(path))
+}
+
+// NewStateBytes creates a lexer reading directly from path.
+// The slice is not copied and must not be modified while parsing.
+func NewStateBytes(path []byte) *State {
+	return newState(bytes.NewReader(path))
+}
+
+// NewStateReader creates a lexer reading from r. Input is consumed
+// incrementally; r is wrapped into a bufio.Reader unless it already
+// implements io.RuneReader.
+func NewStateReader(r io.Reader) *State {
+	rr, ok := r.(io.RuneReader)
+	if !ok {
+		rr = bufio.NewReader(r)
 	}
+	return newState(rr)
 }
 
-func (s *State) SkipSpaces() {
-	for s.index < s.max && isSpace(s.getRuneAtIndex(s.index)) {
-		s.index++
+func newState(src io.RuneReader) *State {
+	s := &State{
+		src:    src,
+		result: []*Segment{},
+		data:   []float64{},
+		line:   1,
 	}
+	s.next()
+	return s
 }
 
-func (s *State) getRuneAtIndex(index int) rune {
-	i := 0
-	for _, ch := range s.path {
-		if i == index {
-			return ch
+// Advance to the next rune of input
+//
+func (s *State) next() {
+	s.index += s.width
+	if s.ch == 0x0A /* \n */ {
+		s.line++
+		s.column = 0
+		s.window = s.window[:0]
+	}
+	if s.readErr != nil {
+		s.ch, s.width = eof, 0
+		return
+	}
+	ch, width, err := s.src.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			s.readErr = errors.Wrap(err, "SvgPath: failed to read path")
 		}
-		i++
+		s.ch, s.width = eof, 0
+		return
+	}
+	s.ch, s.width = ch, width
+	s.column++
+
+	if len(s.window) > 2*snippetContext {
+		s.window = s.window[:copy(s.window, s.window[len(s.window)-snippetContext:])]
+	}
+	s.window = appendRune(s.window, ch)
+}
+
+func appendRune(b []byte, ch rune) []byte {
+	var tmp [utf8.UTFMax]byte
+	n := utf8.EncodeRune(tmp[:], ch)
+	return append(b, tmp[:n]...)
+}
+
+// Record a parse error. Offset may point back into the number being
+// scanned, token is the text scanned so far plus the current rune.
+//
+func (s *State) fail(offset int, expected string, format string, args ...interface{}) error {
+	token := append([]byte{}, s.buf...)
+	if s.ch != eof {
+		token = appendRune(token, s.ch)
+	}
+	s.err = &ParseError{
+		Offset:   offset,
+		Line:     s.line,
+		Column:   s.column - (s.index - offset),
+		Token:    string(token),
+		Expected: expected,
+		Snippet:  s.snippet(),
+		msg:      fmt.Sprintf(format, args...),
+	}
+	return s.err
+}
+
+// Source text of the current line around ch. Reads a few more runes
+// of input, so it is only usable once parsing has stopped.
+//
+func (s *State) snippet() string {
+	b := append([]byte{}, s.window...)
+	if s.ch != eof && s.ch != 0x0A /* \n */ && s.ch != 0x0D /* \r */ {
+		for i := 0; i < snippetContext && s.readErr == nil; i++ {
+			ch, _, err := s.src.ReadRune()
+			if err != nil || ch == 0x0A /* \n */ || ch == 0x0D /* \r */ {
+				break
+			}
+			b = appendRune(b, ch)
+		}
+	}
+	return strings.TrimRight(string(b), "\r\n")
+}
+
+// Append current rune to the number buffer and advance
+//
+func (s *State) consume() {
+	s.buf = append(s.buf, byte(s.ch))
+	s.next()
+}
+
+func (s *State) SkipSpaces() {
+	for s.ch != eof && isSpace(s.ch) {
+		s.next()
 	}
-	return 0
 }
 
 func (s *State) ScanParam() error {
 	start := s.index
-	index := start
-	max := s.max
 	zeroFirst := false
 	hasCeiling := false
 	hasDecimal := false
 	hasDot := false
 
-	if index >= max {
-		s.err = errors.Errorf("SvgPath: missed param (at pos %d)", index)
-		return s.err
+	s.buf = s.buf[:0]
+
+	if s.ch == eof {
+		return s.fail(s.index, "number", "missed param (at pos %d)", s.index)
 	}
-	ch := s.getRuneAtIndex(index)
 
-	if ch == 0x2B /* + */ || ch == 0x2D /* - */ {
-		index++
-		ch = s.getRuneAtIndex(index)
+	if s.ch == 0x2B /* + */ || s.ch == 0x2D /* - */ {
+		s.consume()
 	}
 
 	// This logic is shamelessly borrowed from Esprima
 	// https://github.com/ariya/esprimas
 	//
-	if !isDigit(ch) && ch != 0x2E /* . */ {
-		s.err = errors.Errorf("SvgPath: param should start with 0..9 or `.` (at pos %d)", index)
-		return s.err
+	if !isDigit(s.ch) && s.ch != 0x2E /* . */ {
+		return s.fail(s.index, "number", "param should start with 0..9 or `.` (at pos %d)", s.index)
 	}
 
-	if ch != 0x2E /* . */ {
-		zeroFirst = (ch == 0x30 /* 0 */)
-		index++
-
-		ch = s.getRuneAtIndex(index)
+	if s.ch != 0x2E /* . */ {
+		zeroFirst = (s.ch == 0x30 /* 0 */)
+		s.consume()
 
-		if zeroFirst && index < max {
-			// decimal number starts with '0' such as '09' is illegal.
-			if ch != 0 && isDigit(ch) {
-				s.err = errors.Errorf("SvgPath: numbers started with `0` such as `09` are ilegal (at pos %d)", start)
-				return s.err
-			}
+		// decimal number starts with '0' such as '09' is illegal.
+		if zeroFirst && isDigit(s.ch) {
+			return s.fail(start, "`.`, exponent or separator", "numbers started with `0` such as `09` are ilegal (at pos %d)", start)
 		}
 
-		for {
-			if index >= max {
-				break
-			} else if !isDigit(s.getRuneAtIndex(index)) {
-				break
-			}
-			index++
+		for isDigit(s.ch) {
+			s.consume()
 			hasCeiling = true
 		}
-		ch = s.getRuneAtIndex(index)
 	}
 
-	if ch == 0x2E /* . */ {
+	if s.ch == 0x2E /* . */ {
 		hasDot = true
-		index++
-		for {
-			if !isDigit(s.getRuneAtIndex(index)) {
-				break
-			}
-			index++
+		s.consume()
+		for isDigit(s.ch) {
+			s.consume()
 			hasDecimal = true
-
 		}
-		ch = s.getRuneAtIndex(index)
 	}
 
-	if ch == 0x65 /* e */ || ch == 0x45 /* E */ {
+	if s.ch == 0x65 /* e */ || s.ch == 0x45 /* E */ {
 		if hasDot && !hasCeiling && !hasDecimal {
-			s.err = errors.Errorf("SvgPath: invalid float exponent (at pos %d)", index)
-			return s.err
+			return s.fail(s.index, "digit", "invalid float exponent (at pos %d)", s.index)
 		}
 
-		index++
+		s.consume()
 
-		ch = s.getRuneAtIndex(index)
-		if ch == 0x2B /* + */ || ch == 0x2D /* - */ {
-			index++
+		if s.ch == 0x2B /* + */ || s.ch == 0x2D /* - */ {
+			s.consume()
 		}
-		if index < max && isDigit(s.getRuneAtIndex(index)) {
-			for {
-				if index >= max {
-					break
-				} else if !isDigit(s.getRuneAtIndex(index)) {
-					break
-				}
-				index++
-			}
-		} else {
-			s.err = errors.Errorf("SvgPath: invalid float exponent (at pos %d)", index)
-			return s.err
+		if !isDigit(s.ch) {
+			return s.fail(s.index, "exponent digit", "invalid float exponent (at pos %d)", s.index)
+		}
+		for isDigit(s.ch) {
+			s.consume()
 		}
 	}
 
-	value := ""
-	s.index = index
-	for i := start; i < index; i++ {
-		value = fmt.Sprintf("%s%c", value, s.getRuneAtIndex(i))
-	}
-	param, err := strconv.ParseFloat(value, 64)
+	param, err := strconv.ParseFloat(string(s.buf), 64)
 	if err != nil {
-		return errors.Wrap(err, "Failed to parse param")
+		// only possible for out of range values
+		return s.fail(start, "number in float64 range", "param is out of range (at pos %d)", start)
 	}
 	s.param = param
 	return nil
 }
 
+// Scan arc flag, which is exactly one `0` or `1` character
+//
+func (s *State) ScanFlag() error {
+	s.buf = s.buf[:0]
+
+	if s.ch == eof {
+		return s.fail(s.index, "flag", "missed param (at pos %d)", s.index)
+	}
+
+	if s.ch != 0x30 /* 0 */ && s.ch != 0x31 /* 1 */ {
+		return s.fail(s.index, "flag", "arc flag should be 0 or 1 (at pos %d)", s.index)
+	}
+
+	s.param = float64(s.ch - 0x30)
+	s.next()
+	return nil
+}
+
 func (s *State) FinalizeSegment() {
 	// Process duplicated commands (without comand name)
 
 	// This logic is shamelessly borrowed from Raphael
 	// https://github.com/DmitryBaranovskiy/raphael/
 	//
-	cmd := fmt.Sprintf("%c", s.getRuneAtIndex(s.segmentStart))
-	cmdLC := strings.ToLower(cmd)
-
+	cmd := s.cmd
 	params := s.data
 
-	if cmdLC == "m" && len(params) > 2 {
-		s.result = append(s.result, &Segment{Command: cmd, Params: []float64{params[0], params[1]}})
+	if cmd|0x20 == 0x6D /* m */ && len(params) > 2 {
+		s.emit(cmd, params[:2])
 		params = params[2:]
-		cmdLC = "l"
-		if cmd == "m" {
-			cmd = "l"
-		} else {
-			cmd = "L"
-		}
+		// m -> l, M -> L
+		cmd -= 0x6D - 0x6C
 	}
 
-	if cmdLC == "r" {
-		result := &Segment{Command: cmd, Params: []float64{}}
-		for _, param := range params {
-			result.Params = append(result.Params, param)
-		}
-		s.result = append(s.result, result)
+	if cmd|0x20 == 0x72 /* r */ {
+		s.emit(cmd, params)
 	} else {
-		for len(params) >= paramCounts[cmdLC] {
-			result := &Segment{Command: cmd, Params: []float64{}}
-			for i := 0; i < paramCounts[cmdLC]; i++ {
-				result.Params = append(result.Params, params[i])
-			}
-			params = params[paramCounts[cmdLC]:]
-			s.result = append(s.result, result)
-			if paramCounts[cmdLC] == 0 {
+		count := Command(cmd).ParamCount()
+		for len(params) >= count {
+			s.emit(cmd, params[:count])
+			params = params[count:]
+			if count == 0 {
 				break
 			}
 		}
 	}
 }
 
-func (s *State) ScanSegment() error {
-	max := s.max
+// Pass complete segment to the handler, or append it to result
+//
+func (s *State) emit(cmd rune, params []float64) {
+	s.count++
+	if s.handler != nil {
+		s.handler.segment(cmd, params)
+		return
+	}
+	s.result = append(s.result, &Segment{Command: string(cmd), Params: append([]float64{}, params...)})
+}
+
+// Finalize the params scanned so far, dropping an incomplete tail
+//
+func (s *State) flushComplete(needParams int) {
+	s.data = s.data[:len(s.data)-len(s.data)%needParams]
+	if len(s.data) > 0 {
+		s.FinalizeSegment()
+	}
+}
 
+func (s *State) ScanSegment() error {
 	s.segmentStart = s.index
-	cmdCode := s.getRuneAtIndex(s.index)
+	s.cmd = s.ch
+
+	if !isCommand(s.cmd) {
+		return s.fail(s.index, "command", "bad command %c (at pos %d)", s.cmd, s.index)
+	}
 
-	if !isCommand(cmdCode) {
-		s.err = errors.Errorf("SvgPath: bad command %c (at pos %d)", s.getRuneAtIndex(s.index), s.index)
-		return s.err
+	if s.count == 0 && s.cmd|0x20 != 0x6D /* m */ {
+		return s.fail(s.index, "`M` or `m`", "string should start with `M` or `m`")
 	}
 
-	need_params := paramCounts[strings.ToLower(fmt.Sprintf("%c", s.getRuneAtIndex(s.index)))]
+	need_params := Command(s.cmd).ParamCount()
 
-	s.index++
+	s.next()
 	s.SkipSpaces()
 
-	s.data = []float64{}
+	s.data = s.data[:0]
 
 	if need_params == 0 {
 		// Z
@@ -289,12 +409,20 @@ func (s *State) ScanSegment() error {
 		return nil
 	}
 
+	isArc := s.cmd|0x20 == 0x61 /* a */
 	comma_found := false
 
 	for {
 		for i := need_params; i > 0; i-- {
-			s.ScanParam()
-			if s.err != nil {
+			scan := s.ScanParam
+			// large-arc-flag and sweep-flag of `A` are single digits,
+			// so separators after them are optional
+			if isArc && (i == 4 || i == 3) {
+				scan = s.ScanFlag
+			}
+			if scan() != nil {
+				// keep complete segments of an implicitly repeated command
+				s.flushComplete(need_params)
 				return s.err
 			}
 			s.data = append(s.data, s.param)
@@ -302,24 +430,30 @@ func (s *State) ScanSegment() error {
 			s.SkipSpaces()
 			comma_found = false
 
-			if s.index < max && s.getRuneAtIndex(s.index) == 0x2C /* , */ {
-				s.index++
+			if s.ch == 0x2C /* , */ {
+				s.next()
 				s.SkipSpaces()
 				comma_found = true
 			}
 		}
 
+		// Catmull-Rom needs 2 points at least, and takes any number of
+		// points after that
+		if s.cmd|0x20 == 0x72 /* r */ {
+			need_params = 2
+		}
+
 		// after ',' param is mandatory
 		if comma_found {
 			continue
 		}
 
-		if s.index >= s.max {
+		if s.ch == eof {
 			break
 		}
 
 		// Stop on next segment
-		if !isDigitStart(s.getRuneAtIndex(s.index)) {
+		if !isDigitStart(s.ch) {
 			break
 		}
 	}
@@ -328,34 +462,95 @@ func (s *State) ScanSegment() error {
 	return nil
 }
 
-/* Returns array of segments:
- *
- * [
- *   [ command, coord1, coord2, ... ]
- * ]
- */
-func PathParse(svgPath string) ([]*Segment, error) {
-	s := NewState(svgPath)
-	max := s.max
+// Run the lexer over the whole input, passing segments to h
+//
+func (s *State) walk(h PathHandler) error {
+	s.handler = newPathResolver(h)
+	_, err := s.parse(false)
+	return err
+}
 
+// Run the lexer over the whole input and validate the result.
+// In lenient mode segments before an error are kept.
+//
+func (s *State) parse(lenient bool) ([]*Segment, error) {
 	s.SkipSpaces()
 
-	for s.index < max && s.err == nil {
+	for s.ch != eof && s.err == nil {
 		s.ScanSegment()
 	}
 
-	if s.err != nil {
-		s.result = []*Segment{}
+	if s.readErr != nil {
+		s.err = s.readErr
+	}
 
-	} else if len(s.result) > 0 {
+	if len(s.result) > 0 {
+		s.result[0].Command = "M"
+	}
 
-		if !(s.result[0].Command == "m" || s.result[0].Command == "M") {
-			s.err = errors.Errorf("SvgPath: string should start with `M` or `m`")
+	if s.err != nil {
+		if e, ok := s.err.(*ParseError); ok {
+			e.Segments = s.result
+		}
+		if !lenient {
 			s.result = []*Segment{}
-		} else {
-			s.result[0].Command = "M"
 		}
 	}
 
 	return s.result, s.err
 }
+
+/* Returns array of segments:
+ *
+ * [
+ *   [ command, coord1, coord2, ... ]
+ * ]
+ *
+ * Syntax errors are reported as *ParseError, which also holds
+ * the segments parsed before the error.
+ */
+func PathParse(svgPath string) ([]*Segment, error) {
+	return NewState(svgPath).parse(false)
+}
+
+// Same as PathParse, but reads path data from a byte slice without
+// converting it to a string first
+//
+func PathParseBytes(svgPath []byte) ([]*Segment, error) {
+	return NewStateBytes(svgPath).parse(false)
+}
+
+// Same as PathParse, but reads path data from r until EOF. Read errors
+// other than io.EOF are returned wrapped, syntax errors as *ParseError.
+//
+func PathParseReader(r io.Reader) ([]*Segment, error) {
+	return NewStateReader(r).parse(false)
+}
+
+// Parse path data the way user agents render it: on error, all complete
+// segments before the error are returned together with the error.
+// An incomplete segment at the error position is dropped.
+//
+func PathParseLenient(svgPath string) ([]*Segment, error) {
+	return NewState(svgPath).parse(true)
+}
+
+// Parse path data and report segments to h as they are scanned,
+// without building segments. On error, h has already received
+// all segments before the error.
+//
+func PathParseTo(svgPath string, h PathHandler) error {
+	return NewState(svgPath).walk(h)
+}
+
+// Same as PathParseTo, but reads path data from a byte slice
+//
+func PathParseBytesTo(svgPath []byte, h PathHandler) error {
+	return NewStateBytes(svgPath).walk(h)
+}
+
+// Same as PathParseTo, but reads path data from r until EOF
+//
+func PathParseReaderTo(r io.Reader, h PathHandler) error {
+	return NewStateReader(r).walk(h)
+}
@@ -2,8 +2,12 @@
 package svgpath
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+	"testing/iotest"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	//"github.com/stretchr/testify/assert"
 )
@@ -81,3 +85,150 @@ func TestErrors(t *testing.T) {
 	_, err = NewSvgPath("M0 .e3")
 	assert.Equal(t, "SvgPath: invalid float exponent (at pos 4)", err.Error())
 }
+
+func TestParseBytesAndReader(t *testing.T) {
+	expected, err := PathParse("M10 10l20-30c1 2 3 4 5 6z")
+	assert.Nil(t, err)
+
+	segments, err := PathParseBytes([]byte("M10 10l20-30c1 2 3 4 5 6z"))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, segments)
+
+	segments, err = PathParseReader(strings.NewReader("M10 10l20-30c1 2 3 4 5 6z"))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, segments)
+
+	// plain io.Reader, without ReadRune
+	segments, err = PathParseReader(iotest.OneByteReader(strings.NewReader("M10 10l20-30c1 2 3 4 5 6z")))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, segments)
+}
+
+func TestParseReaderError(t *testing.T) {
+	_, err := PathParseReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("M10 10"))))
+	assert.NotNil(t, err)
+	assert.Equal(t, iotest.ErrTimeout, errors.Cause(err))
+}
+
+func TestParseLongPath(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("M0 0")
+	for i := 0; i < 100000; i++ {
+		b.WriteString("l1.5-2.5")
+	}
+	segments, err := PathParseBytes(b.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, 100001, len(segments))
+	assert.Equal(t, []float64{1.5, -2.5}, segments[100000].Params)
+}
+
+func TestParseErrorDetails(t *testing.T) {
+	_, err := PathParse("M0 0\nL10 10 20 G30")
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 15, pe.Offset)
+	assert.Equal(t, 2, pe.Line)
+	assert.Equal(t, 11, pe.Column)
+	assert.Equal(t, "G", pe.Token)
+	assert.Equal(t, "number", pe.Expected)
+	assert.Equal(t, "L10 10 20 G30", pe.Snippet)
+	assert.Equal(t, "SvgPath: param should start with 0..9 or `.` (at pos 15)", err.Error())
+
+	_, err = PathParse("M0 0 L 012")
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 7, pe.Offset)
+	assert.Equal(t, 8, pe.Column)
+	assert.Equal(t, "01", pe.Token)
+
+	_, err = PathParse("M0 0 L 1e")
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 9, pe.Offset)
+	assert.Equal(t, "1e", pe.Token)
+	assert.Equal(t, "exponent digit", pe.Expected)
+
+	_, err = PathParse("M0 0 L1 1e999")
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "SvgPath: param is out of range (at pos 8)", err.Error())
+}
+
+func TestParseErrorSegments(t *testing.T) {
+	segments, err := PathParse("M10 10 L20 20 30 30 C1 2 3")
+	assert.Equal(t, 0, len(segments))
+
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, []*Segment{
+		{Command: "M", Params: []float64{10, 10}},
+		{Command: "L", Params: []float64{20, 20}},
+		{Command: "L", Params: []float64{30, 30}},
+	}, pe.Segments)
+}
+
+func TestParseErrorSnippet(t *testing.T) {
+	long := strings.Repeat("L1 1", 50)
+	_, err := PathParse("M0 0" + long + "X" + long)
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 204, pe.Offset)
+	assert.True(t, len(pe.Snippet) <= 4*snippetContext)
+	assert.True(t, strings.HasSuffix(pe.Snippet[:strings.Index(pe.Snippet, "X")], "L1 1L1 1"))
+	assert.True(t, strings.HasPrefix(pe.Snippet[strings.Index(pe.Snippet, "X"):], "XL1 1L1 1"))
+}
+
+func TestParseLenient(t *testing.T) {
+	segments, err := PathParseLenient("M10 10 L20 20 30 30 40")
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "SvgPath: missed param (at pos 22)", err.Error())
+	assert.Equal(t, []*Segment{
+		{Command: "M", Params: []float64{10, 10}},
+		{Command: "L", Params: []float64{20, 20}},
+		{Command: "L", Params: []float64{30, 30}},
+	}, segments)
+	assert.Equal(t, segments, pe.Segments)
+
+	sp, err := NewSvgPathLenient("m10 10 20 20 h10 v 10 c 1 2 3 x")
+	assert.NotNil(t, err)
+	assert.Equal(t, "M10 10l20 20h10v10", sp.ToString())
+
+	sp, err = NewSvgPathLenient("M10 10 L20 20")
+	assert.Nil(t, err)
+	assert.Equal(t, "M10 10L20 20", sp.ToString())
+
+	sp, err = NewSvgPathLenient("L20 20")
+	assert.NotNil(t, err)
+	assert.Equal(t, "", sp.ToString())
+}
+
+func TestCatmullRomParams(t *testing.T) {
+	segments, err := PathParse("M0 0R1 1 2 2 3 3")
+	assert.Nil(t, err)
+	assert.Equal(t, &Segment{Command: "R", Params: []float64{1, 1, 2, 2, 3, 3}}, segments[1])
+
+	_, err = PathParse("M0 0R1 1")
+	assert.Equal(t, "SvgPath: missed param (at pos 8)", err.Error())
+}
+
+func TestArcFlags(t *testing.T) {
+	sp, err := NewSvgPath("M0 0a1 1 0 00 1 1")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0a1 1 0 0 0 1 1", sp.ToString())
+
+	sp, err = NewSvgPath("M0 0A5 5 0 1010 10")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0A5 5 0 1 0 10 10", sp.ToString())
+
+	sp, err = NewSvgPath("M0 0a1 1 0 1 1 1 1 2 2 0 0,0-1-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0a1 1 0 1 1 1 1 2 2 0 0 0-1-1", sp.ToString())
+
+	sp, err = NewSvgPath("M0 0a1 1 0 11.5.5")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0a1 1 0 1 1 0.5 0.5", sp.ToString())
+
+	_, err = NewSvgPath("M0 0a1 1 0 2 1 1 1")
+	assert.Equal(t, "SvgPath: arc flag should be 0 or 1 (at pos 11)", err.Error())
+
+	_, err = NewSvgPath("M0 0a1 1 0 0")
+	assert.Equal(t, "SvgPath: missed param (at pos 12)", err.Error())
+}
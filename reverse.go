@@ -0,0 +1,204 @@
+package svgpath
+
+// Reverse drawing direction of the path: subpaths and their segments go
+// in reverse order, every segment is drawn from its end to its start.
+// Segments keep their commands and relative form: arcs flip the sweep
+// flag, `H`/`V` stay horizontal and vertical lines, shorthand curves
+// move to the segments whose control points are reflected in the
+// reversed path. Catmull-Rom splines become cubic curves. Pending
+// transforms are applied first.
+//
+func (sp *SvgPath) Reverse() *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
+	sp.evaluateStack()
+
+	rec := &reverseRecorder{}
+	r := newPathResolver(rec)
+	for _, s := range sp.segments {
+		// same rules as contours: skip broken segments and drawing
+		// before the first moveto
+		if len(s.Command) != 1 {
+			continue
+		}
+		if len(rec.subpaths) == 0 && s.Command != "M" && s.Command != "m" {
+			continue
+		}
+		rec.cmd = s.Command[0] &^ 0x20
+		rec.rel = s.Command[0]|0x20 == s.Command[0]
+		r.segment(rune(s.Command[0]), s.Params)
+	}
+
+	segments := []*Segment{}
+	x, y := 0.0, 0.0
+	for i := len(rec.subpaths) - 1; i >= 0; i-- {
+		segments = rec.subpaths[i].appendReversed(segments, &x, &y)
+	}
+	sp.segments = segments
+	return sp
+}
+
+// Drawn segment with absolute coordinates
+type reverseItem struct {
+	cmd    byte // source command, in upper case
+	rel    bool
+	kind   primKind
+	x0, y0 float64   // start point
+	p      []float64 // params of the PathHandler call
+}
+
+type reverseSubpath struct {
+	rel    bool // relative moveto
+	x, y   float64
+	items  []reverseItem
+	closed bool
+}
+
+// PathHandler collecting subpaths
+type reverseRecorder struct {
+	// command of the segment being resolved
+	cmd byte
+	rel bool
+
+	x, y     float64
+	subpaths []*reverseSubpath
+}
+
+func (rec *reverseRecorder) MoveTo(x, y float64) {
+	rec.subpaths = append(rec.subpaths, &reverseSubpath{rel: rec.rel, x: x, y: y})
+	rec.x, rec.y = x, y
+}
+
+func (rec *reverseRecorder) draw(kind primKind, p ...float64) {
+	// drawing after `z` starts a new subpath at the same point
+	if n := len(rec.subpaths); n == 0 || rec.subpaths[n-1].closed {
+		rec.subpaths = append(rec.subpaths, &reverseSubpath{x: rec.x, y: rec.y})
+	}
+
+	s := rec.subpaths[len(rec.subpaths)-1]
+	s.items = append(s.items, reverseItem{cmd: rec.cmd, rel: rec.rel, kind: kind, x0: rec.x, y0: rec.y, p: p})
+	rec.x, rec.y = p[len(p)-2], p[len(p)-1]
+}
+
+func (rec *reverseRecorder) LineTo(x, y float64) {
+	rec.draw(primLine, x, y)
+}
+
+func (rec *reverseRecorder) QuadTo(x1, y1, x, y float64) {
+	rec.draw(primQuad, x1, y1, x, y)
+}
+
+func (rec *reverseRecorder) CubicTo(x1, y1, x2, y2, x, y float64) {
+	rec.draw(primCubic, x1, y1, x2, y2, x, y)
+}
+
+func (rec *reverseRecorder) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) {
+	rec.draw(primArc, rx, ry, rotation, flag(largeArc), flag(sweep), x, y)
+}
+
+func (rec *reverseRecorder) Close() {
+	// repeated `z` draws nothing
+	if n := len(rec.subpaths); n > 0 && !rec.subpaths[n-1].closed {
+		s := rec.subpaths[n-1]
+		s.closed = true
+		rec.x, rec.y = s.x, s.y
+	}
+}
+
+// Append reversed subpath, (x, y) is the current point before and
+// after it
+//
+func (s *reverseSubpath) appendReversed(segments []*Segment, x, y *float64) []*Segment {
+	endX, endY := s.x, s.y
+	if n := len(s.items); n > 0 {
+		p := s.items[n-1].p
+		endX, endY = p[len(p)-2], p[len(p)-1]
+	}
+
+	if s.rel {
+		segments = append(segments, &Segment{Command: "m", Params: []float64{endX - *x, endY - *y}})
+	} else {
+		segments = append(segments, &Segment{Command: "M", Params: []float64{endX, endY}})
+	}
+
+	curX, curY := endX, endY
+	for j := len(s.items) - 1; j >= 0; j-- {
+		it := &s.items[j]
+
+		// the next segment reflected control point of this one, now
+		// this one reflects the control point of the next
+		short := false
+		if j+1 < len(s.items) {
+			next := s.items[j+1].cmd
+			short = next == 'S' && it.kind == primCubic || next == 'T' && it.kind == primQuad
+		}
+
+		seg := it.reversed(short)
+		if it.rel {
+			seg.toRelative(curX, curY)
+		}
+		segments = append(segments, seg)
+		curX, curY = it.x0, it.y0
+	}
+
+	if s.closed {
+		segments = append(segments, &Segment{Command: "Z", Params: []float64{}})
+		curX, curY = endX, endY
+	}
+	*x, *y = curX, curY
+	return segments
+}
+
+// Absolute segment drawn from the item end to its start
+//
+func (it *reverseItem) reversed(short bool) *Segment {
+	p := it.p
+	switch it.kind {
+	case primQuad:
+		if short {
+			return &Segment{Command: "T", Params: []float64{it.x0, it.y0}}
+		}
+		return &Segment{Command: "Q", Params: []float64{p[0], p[1], it.x0, it.y0}}
+	case primCubic:
+		if short {
+			return &Segment{Command: "S", Params: []float64{p[0], p[1], it.x0, it.y0}}
+		}
+		return &Segment{Command: "C", Params: []float64{p[2], p[3], p[0], p[1], it.x0, it.y0}}
+	case primArc:
+		return &Segment{Command: "A", Params: []float64{p[0], p[1], p[2], p[3], 1 - p[4], it.x0, it.y0}}
+	}
+
+	switch it.cmd {
+	case 'H':
+		return &Segment{Command: "H", Params: []float64{it.x0}}
+	case 'V':
+		return &Segment{Command: "V", Params: []float64{it.y0}}
+	}
+	return &Segment{Command: "L", Params: []float64{it.x0, it.y0}}
+}
+
+// Convert absolute segment to relative one at the current point (x, y)
+//
+func (s *Segment) toRelative(x, y float64) {
+	switch s.Command {
+	case "H":
+		s.Params[0] -= x
+	case "V":
+		s.Params[0] -= y
+	case "A":
+		s.Params[5] -= x
+		s.Params[6] -= y
+	default:
+		for i := range s.Params {
+			// odd values are Y, even - X
+			if i%2 == 0 {
+				s.Params[i] -= x
+			} else {
+				s.Params[i] -= y
+			}
+		}
+	}
+	s.Command = string(s.Command[0] | 0x20)
+}
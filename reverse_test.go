@@ -0,0 +1,96 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseLines(t *testing.T) {
+	assert.Equal(t, "M10 10L10 0 0 0", Parse("M0 0L10 0L10 10").Reverse().ToString())
+	assert.Equal(t, "M10 10V0H0", Parse("M0 0H10V10").Reverse().ToString())
+	assert.Equal(t, "M10 10v-10h-10", Parse("m0 0h10v10").Reverse().ToString())
+	assert.Equal(t, "M10 10l-10-10", Parse("M0 0l10 10").Reverse().ToString())
+}
+
+func TestReverseSubpaths(t *testing.T) {
+	assert.Equal(t, "M15 5L10 0M5 0H0", Parse("M0 0H5M10 0L15 5").Reverse().ToString())
+	assert.Equal(t, "M0 10H10V0H0Z", Parse("M0 0H10V10H0Z").Reverse().ToString())
+
+	// relative moveto is taken from the new current point
+	assert.Equal(t, "m25 5h-5m-5 0L10 0M5 0H0", Parse("M0 0H5m5 0L15 5m5 0h5").Reverse().ToString())
+
+	// drawing after `z` starts a subpath with its own moveto
+	assert.Equal(t, "M5 5L0 0M10 0H0Z", Parse("M0 0H10zL5 5").Reverse().ToString())
+	assert.Equal(t, "M5 5", Parse("M5 5").Reverse().ToString())
+	assert.Equal(t, "", Parse("").Reverse().ToString())
+}
+
+func TestReverseCurves(t *testing.T) {
+	assert.Equal(t, "M30 0C20 10 10 10 0 0", Parse("M0 0C10 10 20 10 30 0").Reverse().ToString())
+	assert.Equal(t, "M20 0Q10 10 0 0", Parse("M0 0Q10 10 20 0").Reverse().ToString())
+	assert.Equal(t, "M20 0A10 10 0 0 0 0 0", Parse("M0 0A10 10 0 0 1 20 0").Reverse().ToString())
+	assert.Equal(t, "M20 0a10 5 30 1 1-20 0", Parse("m0 0a10 5 30 1 0 20 0").Reverse().ToString())
+
+	// shorthand moves to the segment before
+	assert.Equal(t, "M60 0C50-10 40-10 30 0S10 10 0 0",
+		Parse("M0 0C10 10 20 10 30 0S50-10 60 0").Reverse().ToString())
+	assert.Equal(t, "M40 0Q30-10 20 0T0 0", Parse("M0 0Q10 10 20 0T40 0").Reverse().ToString())
+
+	// shorthand without a curve before gets its control point
+	assert.Equal(t, "M20 0C10 10 0 0 0 0", Parse("M0 0S10 10 20 0").Reverse().ToString())
+	assert.Equal(t, "M20 0Q0 0 0 0", Parse("M0 0T20 0").Reverse().ToString())
+
+	// Catmull-Rom spline becomes cubic curves
+	assert.Equal(t, Parse("M0 0R10 10 20 0").Uncatmull().Reverse().ToString(),
+		Parse("M0 0R10 10 20 0").Reverse().ToString())
+}
+
+func TestReverseGeometry(t *testing.T) {
+	paths := []string{
+		"M0 0C10 10 20 10 30 0S50-10 60 0s20 10 30 0",
+		"m0 0q10 10 20 0t20 0 20 0z",
+		"M0 0R10 10 20 0 30 10 40 0h10a5 5 0 0 1 10 0v10z",
+		"M0 0h10v10zM0 0l-5 5m20 0l5 5",
+	}
+	for _, path := range paths {
+		sp := Parse(path)
+		rev := sp.Clone().Reverse()
+		assert.InDelta(t, -sp.Area(), rev.Area(), 1e-9, path)
+		assert.InDelta(t, sp.Length(), rev.Length(), 1e-6, path)
+
+		// twice reversed path is the same geometry
+		back := rev.Clone().Reverse()
+		assert.Equal(t, sp.Unshort().Uncatmull().Abs().Round(6).ToString(),
+			back.Unshort().Uncatmull().Abs().Round(6).ToString(), path)
+	}
+}
+
+func TestReverseBrokenSegments(t *testing.T) {
+	sp := &SvgPath{
+		segments: []*Segment{
+			{Command: "L", Params: []float64{5, 5}},
+			{Command: "M", Params: []float64{0, 0}},
+			{Command: "", Params: []float64{}},
+			{Command: "H", Params: []float64{10}},
+			{Command: "V", Params: []float64{10}},
+		},
+		stack: []*Matrix{},
+	}
+	area := sp.Area()
+	length := sp.Length()
+
+	rev := sp.Clone().Reverse()
+	assert.Equal(t, "M10 10V0H0", rev.ToString())
+	assert.Equal(t, -area, rev.Area())
+	assert.Equal(t, length, rev.Length())
+}
+
+func TestReverseTransformed(t *testing.T) {
+	assert.Equal(t, "M20 20L0 0", Parse("M0 0L10 10").Scale(2, 2).Reverse().ToString())
+}
+
+func TestReverseError(t *testing.T) {
+	sp := Parse("M0 0X").Reverse()
+	assert.Error(t, sp.Err())
+}
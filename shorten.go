@@ -0,0 +1,175 @@
+package svgpath
+
+import (
+	"math"
+	"strings"
+)
+
+// Converts segments to shorthand `H`, `V`, `S` and `T` where the result
+// differs from the original by no more than tolerance. Relative segments
+// following a snapped point are corrected to land on their exact points,
+// so deviations don't add up. Inverse of Unshort.
+//
+func (sp *SvgPath) Shorten(tolerance float64) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
+	// Current point, subpath start and last control point, as drawn by
+	// the shortened path. They may differ from the exact ones (passed
+	// to the iterator) by up to tolerance.
+	var curX, curY, startX, startY, ctrlX, ctrlY float64
+	prev := ""
+
+	near := func(a, b float64) bool {
+		return math.Abs(a-b) <= tolerance
+	}
+
+	sp.iterate(func(s *Segment, index int, x float64, y float64) []*Segment {
+		name := s.Command
+		nameUC := strings.ToUpper(name)
+		isRelative := name != nameUC
+		p := s.Params
+
+		// ax, ay - base of absolute coordinates, ox, oy - correction of
+		// relative ones for the shortened current point
+		var ax, ay, ox, oy float64
+		if isRelative {
+			ax, ay = x, y
+			ox, oy = x-curX, y-curY
+		}
+
+		// reflection of the previous control point, for `S` and `T`
+		reflect := func(families string) (float64, float64) {
+			if prev != "" && strings.Contains(families, prev) {
+				return 2*curX - ctrlX, 2*curY - ctrlY
+			}
+			return curX, curY
+		}
+
+		// corrected copy of point params
+		shift := func(params []float64) []float64 {
+			res := make([]float64, len(params))
+			for i := 0; i < len(params); i += 2 {
+				res[i] = params[i] + ox
+				res[i+1] = params[i+1] + oy
+			}
+			return res
+		}
+
+		// replacement for the segment, nil when nothing changed
+		result := func(cmd string, params []float64) []*Segment {
+			if isRelative {
+				cmd = strings.ToLower(cmd)
+			}
+			if cmd == name && len(params) == len(p) {
+				same := true
+				for i := range params {
+					same = same && params[i] == p[i]
+				}
+				if same {
+					return nil
+				}
+			}
+			return []*Segment{{Command: cmd, Params: params}}
+		}
+
+		var res []*Segment
+
+		switch nameUC {
+		case "M":
+			res = result("M", shift(p))
+			curX, curY = ax+p[0], ay+p[1]
+			startX, startY = curX, curY
+
+		case "Z":
+			curX, curY = startX, startY
+
+		case "L", "H", "V":
+			// exact end point, and its offset from the shortened current point
+			var ex, ey, dx, dy float64
+			switch nameUC {
+			case "H":
+				ex, ey = ax+p[0], y
+				dx = p[0]
+			case "V":
+				ex, ey = x, ay+p[0]
+				dy = p[0]
+			default:
+				ex, ey = ax+p[0], ay+p[1]
+				dx, dy = p[0], p[1]
+			}
+			if !isRelative {
+				dx, dy = ex, ey
+			}
+			dx += ox
+			dy += oy
+
+			if near(ey, curY) {
+				res = result("H", []float64{dx})
+				curX = ex
+			} else if near(ex, curX) {
+				res = result("V", []float64{dy})
+				curY = ey
+			} else {
+				res = result("L", []float64{dx, dy})
+				curX, curY = ex, ey
+			}
+
+		case "C":
+			pts := shift(p)
+			rx, ry := reflect("CSR")
+			if near(ax+p[0], rx) && near(ay+p[1], ry) {
+				res = result("S", pts[2:])
+			} else {
+				res = result("C", pts)
+			}
+			ctrlX, ctrlY = ax+p[2], ay+p[3]
+			curX, curY = ax+p[4], ay+p[5]
+
+		case "S":
+			res = result("S", shift(p))
+			ctrlX, ctrlY = ax+p[0], ay+p[1]
+			curX, curY = ax+p[2], ay+p[3]
+
+		case "Q":
+			pts := shift(p)
+			rx, ry := reflect("QT")
+			if near(ax+p[0], rx) && near(ay+p[1], ry) {
+				res = result("T", pts[2:])
+				ctrlX, ctrlY = rx, ry
+			} else {
+				res = result("Q", pts)
+				ctrlX, ctrlY = ax+p[0], ay+p[1]
+			}
+			curX, curY = ax+p[2], ay+p[3]
+
+		case "T":
+			res = result("T", shift(p))
+			ctrlX, ctrlY = reflect("QT")
+			curX, curY = ax+p[0], ay+p[1]
+
+		case "A":
+			// ARC is: ['A', rx, ry, x-axis-rotation, large-arc-flag, sweep-flag, x, y]
+			params := append([]float64{}, p...)
+			params[5] += ox
+			params[6] += oy
+			res = result("A", params)
+			curX, curY = ax+p[5], ay+p[6]
+
+		case "R":
+			// the last cubic of Catmull-Rom has its control point at 1/6
+			// of the way to the previous point
+			l := len(p)
+			res = result("R", shift(p))
+			ctrlX = ax + (p[l-4]+5*p[l-2])/6
+			ctrlY = ay + (p[l-3]+5*p[l-1])/6
+			curX, curY = ax+p[l-2], ay+p[l-1]
+		}
+
+		// shorthand forms reflect the same way as their full forms
+		prev = nameUC
+		return res
+	}, false)
+	return sp
+}
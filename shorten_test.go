@@ -0,0 +1,49 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortenLines(t *testing.T) {
+	sp, err := NewSvgPath("M10 10L20 10L20 20l-10 0l0 -10")
+	assert.Nil(t, err)
+	assert.Equal(t, "M10 10H20V20h-10v-10", sp.Shorten(0).ToString())
+}
+
+func TestShortenCurves(t *testing.T) {
+	sp, err := NewSvgPath("M0 0C0 0 10 10 20 0C30 -10 40 0 50 0Q60 10 70 0Q80 -10 90 0")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0S10 10 20 0 40 0 50 0Q60 10 70 0T90 0", sp.Shorten(0).ToString())
+
+	sp, err = NewSvgPath("M0 0c0 0 10 10 20 0c10 -10 20 0 30 0q10 10 20 0q10 -10 20 0")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0s10 10 20 0 20 0 30 0q10 10 20 0t20 0", sp.Shorten(0).ToString())
+
+	// after Catmull-Rom
+	sp, err = NewSvgPath("M0 0R6 0 12 0C13 0 20 5 20 5")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0R6 0 12 0S20 5 20 5", sp.Shorten(0).ToString())
+}
+
+func TestShortenTolerance(t *testing.T) {
+	sp, err := NewSvgPath("M0 0L10 0.01L20 0.02")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0L10 0.01 20 0.02", sp.Clone().Shorten(0).ToString(), "exact only")
+	assert.Equal(t, "M0 0H10 20", sp.Clone().Shorten(0.05).ToString())
+
+	// relative segments after a snapped point land on exact points,
+	// so the second step is not snapped and the error doesn't grow
+	sp, err = NewSvgPath("M0 0l10 0.04l10 0.04l10 0.04")
+	assert.Nil(t, err)
+	assert.Equal(t, "M0 0h10l10 0.08h10", sp.Shorten(0.05).ToString())
+}
+
+func TestShortenTransformed(t *testing.T) {
+	sp, err := NewSvgPath("M0 0L10 10L20 0")
+	assert.Nil(t, err)
+	// rotation leaves float noise in coordinates
+	assert.Equal(t, "M0 0V14.1421356237H14.1421356237",
+		sp.Rotate(45, 0, 0).Shorten(1e-9).Round(10).ToString())
+}
@@ -0,0 +1,131 @@
+package svgpath
+
+import (
+	"math"
+	"sort"
+)
+
+// Part of the path between two distances along it, with pending
+// transforms applied. Curves are cut with de Casteljau algorithm, arcs
+// stay arcs with reduced sweep. Closed subpaths taken as a whole keep
+// their `Z`. sp is not modified.
+//
+func (sp *SvgPath) SubPath(startDist, endDist float64) *SvgPath {
+	pm, err := NewPathMeasure(sp)
+	if err != nil {
+		return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: err}
+	}
+	return pm.SubPath(startDist, endDist)
+}
+
+// Split path in two at the distance along it
+//
+func (sp *SvgPath) SplitAt(dist float64) (*SvgPath, *SvgPath) {
+	pm, err := NewPathMeasure(sp)
+	if err != nil {
+		return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: err},
+			&SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: err}
+	}
+	return pm.SplitAt(dist)
+}
+
+func (pm *PathMeasure) SplitAt(dist float64) (*SvgPath, *SvgPath) {
+	return pm.SubPath(0, dist), pm.SubPath(dist, pm.total)
+}
+
+// Same as SvgPath.SubPath, for many cuts of one path
+//
+func (pm *PathMeasure) SubPath(startDist, endDist float64) *SvgPath {
+	return &SvgPath{segments: pm.appendSubPath(nil, startDist, endDist), stack: []*Matrix{}}
+}
+
+// Append segments drawing the path between distances d0 and d1.
+// Empty range gives nothing.
+//
+func (pm *PathMeasure) appendSubPath(segments []*Segment, d0, d1 float64) []*Segment {
+	d0 = math.Max(0, d0)
+	d1 = math.Min(d1, pm.total)
+	if d1 < d0 || d1 == d0 && pm.total > 0 {
+		return segments
+	}
+
+	for _, c := range pm.contours {
+		if c.first == c.end {
+			// nothing drawn, keep the point if it is in the range
+			if pos := pm.contourStart(c); pos >= d0 && pos <= d1 {
+				segments = append(segments, &Segment{Command: "M", Params: []float64{c.start.X, c.start.Y}})
+				if c.closed {
+					segments = append(segments, &Segment{Command: "Z", Params: []float64{}})
+				}
+			}
+			continue
+		}
+
+		segments = pm.appendContourPart(segments, c, d0, d1)
+	}
+	return segments
+}
+
+// Append segments drawing the part of contour with primitives between
+// distances d0 and d1
+//
+func (pm *PathMeasure) appendContourPart(segments []*Segment, c measureContour, d0, d1 float64) []*Segment {
+	cStart, _ := pm.primRange(c.first)
+	_, cEnd := pm.primRange(c.end - 1)
+	if cEnd <= d0 || cStart >= d1 {
+		return segments
+	}
+	whole := c.closed && d0 <= cStart && d1 >= cEnd
+
+	// primitives ending before d0 draw nothing, nor do ones starting
+	// after d1
+	first := c.first + sort.Search(c.end-c.first, func(k int) bool {
+		_, b := pm.primRange(c.first + k)
+		return b >= d0
+	})
+
+	moved := false
+	for i := first; i < c.end; i++ {
+		a, b := pm.primRange(i)
+		if a > d1 {
+			break
+		}
+		lo, hi := math.Max(a, d0), math.Min(b, d1)
+		// zero length primitives on the range start belong to
+		// the previous part
+		if lo >= hi && !(a == b && (a > d0 || a == 0) && a <= d1) {
+			continue
+		}
+
+		pr := &pm.prims[i]
+		part := *pr
+		if lo > a || hi < b {
+			part = pr.sub(pm.primParam(i, lo), pm.primParam(i, hi))
+		}
+
+		if !moved {
+			x, y := part.start()
+			segments = append(segments, &Segment{Command: "M", Params: []float64{x, y}})
+			moved = true
+		}
+		if whole && pr.closing {
+			continue
+		}
+		segments = append(segments, part.segment())
+	}
+
+	if whole {
+		segments = append(segments, &Segment{Command: "Z", Params: []float64{}})
+	}
+	return segments
+}
+
+// Distance of a contour start
+//
+func (pm *PathMeasure) contourStart(c measureContour) float64 {
+	if c.first < len(pm.prims) {
+		d, _ := pm.primRange(c.first)
+		return d
+	}
+	return pm.total
+}
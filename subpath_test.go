@@ -0,0 +1,83 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Check that path b draws the same points as the part of a starting
+// at distance offset
+func assertSamePoints(t *testing.T, a *SvgPath, offset float64, b *SvgPath, msg string) {
+	ma, err := NewPathMeasure(a)
+	assert.Nil(t, err)
+	mb, err := NewPathMeasure(b)
+	assert.Nil(t, err)
+	for i := 0; i <= 50; i++ {
+		d := mb.Length() * float64(i) / 50
+		assertPoint(t, ma.PointAt(offset+d), mb.PointAt(d), 1e-6, msg)
+	}
+}
+
+func TestSubPathLines(t *testing.T) {
+	sp := Parse("M0 0H10V10H0Z")
+	assert.Equal(t, "M5 0L10 0 10 5", sp.SubPath(5, 15).ToString())
+	assert.Equal(t, "M0 5L0 0", sp.SubPath(35, 40).ToString(), "part of closing line")
+	assert.Equal(t, "M0 0L10 0 10 10 0 10Z", sp.SubPath(0, 40).ToString(), "whole closed subpath")
+	assert.Equal(t, "M0 0L10 0 10 10 0 10Z", sp.SubPath(-5, 100).ToString(), "clamped")
+	assert.Equal(t, "", sp.SubPath(20, 10).ToString(), "empty range")
+
+	first, second := sp.SplitAt(25)
+	assert.Equal(t, "M0 0L10 0 10 10 5 10", first.ToString())
+	assert.Equal(t, "M5 10L0 10 0 0", second.ToString())
+}
+
+func TestSubPathSubpaths(t *testing.T) {
+	sp := Parse("M0 0h10M20 0h10zm0 10h5")
+	assert.Equal(t, "M5 0L10 0M20 0L25 0", sp.SubPath(5, 15).ToString())
+	assert.Equal(t, "M20 0L30 0Z", sp.SubPath(10, 30).ToString())
+	assert.Equal(t, "M22 0L30 0 20 0M20 10L22 10", sp.SubPath(12, 32).ToString())
+
+	first, second := sp.SplitAt(10)
+	assert.Equal(t, "M0 0L10 0", first.ToString())
+	assert.Equal(t, "M20 0L30 0ZM20 10L25 10", second.ToString())
+}
+
+func TestSubPathCurves(t *testing.T) {
+	for _, path := range []string{
+		"M0 0Q50 100 100 0",
+		"M0 0C0 100 100 100 100 0S200 -100 200 0",
+		"M0 0R10 10 20 0 30 10",
+		"M10 0A10 10 0 1 1 -10 0A10 10 0 1 1 10 0",
+		"M0 0A20 10 30 0 0 30 20",
+	} {
+		sp := Parse(path)
+		length := sp.Length()
+		for _, r := range [][2]float64{{0, 1}, {0.1, 0.4}, {0.3, 0.95}, {0.5, 0.5001}} {
+			part := sp.SubPath(length*r[0], length*r[1])
+			assert.InDelta(t, length*(r[1]-r[0]), part.Length(), 1e-6, path)
+			assertSamePoints(t, sp, length*r[0], part, path)
+		}
+	}
+}
+
+func TestSubPathArcs(t *testing.T) {
+	// arcs stay arcs, the sweep changes
+	sp := Parse("M10 0A10 10 0 1 1 -10 0")
+	half := sp.SubPath(0, sp.Length()/2)
+	assert.Equal(t, "M10 0A10 10 0 0 1 0 10", half.Round(6).ToString())
+
+	// transformed and mirrored arc
+	sp = Parse("M0 0A20 10 30 1 0 30 20").Scale(-1, 2).SkewX(10)
+	part := sp.SubPath(5, 50)
+	assert.Equal(t, "A", part.Segments()[1].Command)
+	assertSamePoints(t, sp, 5, part, "transformed")
+}
+
+func TestSubPathError(t *testing.T) {
+	sp := Parse("M0 0").Matrix([]float64{1})
+	assert.NotNil(t, sp.SubPath(0, 1).Err())
+	first, second := sp.SplitAt(1)
+	assert.NotNil(t, first.Err())
+	assert.NotNil(t, second.Err())
+}
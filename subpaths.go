@@ -0,0 +1,75 @@
+package svgpath
+
+// Split path at every moveto. Leading relative `m` of each part is made
+// absolute, so parts can be used on their own. Parts keep pending
+// transforms, sp is not modified.
+//
+func (sp *SvgPath) Subpaths() []*SvgPath {
+	res := []*SvgPath{}
+	if sp.err != nil {
+		return res
+	}
+
+	sp.iterate(func(s *Segment, index int, x float64, y float64) []*Segment {
+		s = s.Clone()
+		if s.Command == "m" || s.Command == "M" {
+			if s.Command == "m" {
+				s.Command = "M"
+				s.Params[0] += x
+				s.Params[1] += y
+			}
+			stack := make([]*Matrix, len(sp.stack))
+			for i, m := range sp.stack {
+				stack[i] = m.Clone()
+			}
+			res = append(res, &SvgPath{segments: []*Segment{}, stack: stack})
+		}
+		last := res[len(res)-1]
+		last.segments = append(last.segments, s)
+		return nil
+	}, true)
+	return res
+}
+
+// Path ends with `z`
+//
+func (sp *SvgPath) Closed() bool {
+	n := len(sp.segments)
+	return n > 0 && (sp.segments[n-1].Command == "Z" || sp.segments[n-1].Command == "z")
+}
+
+// Append other paths to the end of this one. Pending transforms of all
+// paths are applied, leading relative `m` of appended paths is made
+// absolute. Appended paths are not modified, their error becomes the
+// error of the chain.
+//
+func (sp *SvgPath) Append(paths ...*SvgPath) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+	for _, p := range paths {
+		if p.err != nil {
+			sp.err = p.err
+			return sp
+		}
+	}
+
+	sp.evaluateStack()
+	for _, p := range paths {
+		p = p.Clone()
+		p.evaluateStack()
+		// first `m` of a path is absolute
+		if len(p.segments) > 0 && p.segments[0].Command == "m" {
+			p.segments[0].Command = "M"
+		}
+		sp.segments = append(sp.segments, p.segments...)
+	}
+	return sp
+}
+
+// New path made of paths one after another, see SvgPath.Append
+//
+func Concat(paths ...*SvgPath) *SvgPath {
+	sp := &SvgPath{segments: []*Segment{}, stack: []*Matrix{}}
+	return sp.Append(paths...)
+}
@@ -0,0 +1,67 @@
+package svgpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubpaths(t *testing.T) {
+	sp := Parse("M0 0h10v10zm5 5l5 5M20 20L30 30zl1 1")
+	parts := sp.Subpaths()
+
+	strs := []string{}
+	closed := []bool{}
+	for _, p := range parts {
+		strs = append(strs, p.ToString())
+		closed = append(closed, p.Closed())
+	}
+	assert.Equal(t, []string{"M0 0h10v10z", "M5 5l5 5", "M20 20L30 30zl1 1"}, strs)
+	assert.Equal(t, []bool{true, false, false}, closed)
+	assert.Equal(t, "M0 0h10v10zm5 5l5 5M20 20L30 30zl1 1", sp.ToString(), "not modified")
+
+	assert.Equal(t, []*SvgPath{}, Parse("").Subpaths())
+	assert.Equal(t, []*SvgPath{}, Parse("M0 0X").Subpaths())
+}
+
+func TestSubpathsTransformed(t *testing.T) {
+	sp := Parse("M0 0H10m5 5h5").Translate(100, 0)
+	parts := sp.Subpaths()
+	assert.Equal(t, "M100 0H110", parts[0].ToString())
+	assert.Equal(t, "M115 5h5", parts[1].ToString())
+
+	// each part has its own transforms
+	parts[0].Scale(2, 2)
+	assert.Equal(t, "M115 5h5", parts[1].ToString())
+}
+
+func TestClosed(t *testing.T) {
+	assert.True(t, Parse("M0 0H10Z").Closed())
+	assert.True(t, Parse("M0 0H10z").Closed())
+	assert.False(t, Parse("M0 0H10").Closed())
+	assert.False(t, Parse("M0 0ZM5 5").Closed())
+	assert.False(t, Parse("").Closed())
+}
+
+func TestConcat(t *testing.T) {
+	a := Parse("M0 0h10")
+	b := Parse("m5 5l5 5z")
+	assert.Equal(t, "M0 0h10M5 5l5 5z", Concat(a, b).ToString())
+	assert.Equal(t, "M0 0h10", a.ToString(), "not modified")
+	assert.Equal(t, "M5 5l5 5z", b.ToString(), "not modified")
+
+	// subpaths joined back give the same path, with absolute movetos
+	sp := Parse("M0 0h10v10zm5 5l5 5M20 20L30 30zl1 1")
+	assert.Equal(t, "M0 0h10v10zM5 5l5 5M20 20L30 30zl1 1", Concat(sp.Subpaths()...).ToString())
+
+	assert.Equal(t, "", Concat().ToString())
+}
+
+func TestAppend(t *testing.T) {
+	sp := Parse("M0 0h10").Scale(2, 2)
+	sp.Append(Parse("M0 0L1 1").Translate(5, 0), Parse("m1 1h1"))
+	assert.Equal(t, "M0 0h20M5 0L6 1M1 1h1", sp.ToString())
+
+	sp = Parse("M0 0h10").Append(Parse("M0 0X"))
+	assert.Error(t, sp.Err())
+}
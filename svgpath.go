@@ -2,11 +2,10 @@
 package svgpath
 
 import (
-	"fmt"
 	"math"
-	"regexp"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/propellerfactory/cubic2quad"
 )
 
@@ -14,12 +13,16 @@ import (
 //
 // Usage:
 //
-//    SvgPath('...')
-//      .translate(-150, -100)
-//      .scale(0.5)
-//      .translate(-150, -100)
-//      .toFixed(1)
-//      .toString()
+//    d, err := svgpath.Parse("...").
+//      Translate(-150, -100).
+//      Scale(0.5, 0.5).
+//      Translate(-150, -100).
+//      Round(1).
+//      Result()
+//
+// Transforms and normalizations return the path itself, so calls can
+// be chained. The first failed step is remembered and makes the rest
+// of the chain a no-op, check it once at the end with Err or Result.
 //
 
 type Segment struct {
@@ -30,6 +33,7 @@ type Segment struct {
 type SvgPath struct {
 	segments []*Segment
 	stack    []*Matrix
+	err      error
 }
 
 // Class constructor
@@ -46,6 +50,45 @@ func NewSvgPath(path string) (*SvgPath, error) {
 	}, nil
 }
 
+// Same as NewSvgPath, but broken path data is rendered up to the first
+// error, as SVG spec requires. The returned path is never nil, the error
+// (usually *ParseError) describes why parsing stopped.
+//
+func NewSvgPathLenient(path string) (*SvgPath, error) {
+	segments, err := PathParseLenient(path)
+	return &SvgPath{
+		segments: segments,
+		stack:    []*Matrix{},
+	}, err
+}
+
+// Chainable constructor. Parse error, if any, is kept in the path
+// and reported by Err.
+//
+func Parse(path string) *SvgPath {
+	sp, err := NewSvgPath(path)
+	if err != nil {
+		return &SvgPath{segments: []*Segment{}, stack: []*Matrix{}, err: err}
+	}
+	return sp
+}
+
+// First error of the chain
+//
+func (sp *SvgPath) Err() error {
+	return sp.err
+}
+
+// Finish the chain: path string and the first error of the chain.
+// On error the string is empty.
+//
+func (sp *SvgPath) Result() (string, error) {
+	if sp.err != nil {
+		return "", sp.err
+	}
+	return sp.ToString(), nil
+}
+
 func (sp *SvgPath) Segments() []*Segment {
 	return sp.segments
 }
@@ -203,30 +246,7 @@ func (sp *SvgPath) evaluateStack() {
 // Convert processed SVG Path back to string
 //
 func (sp *SvgPath) ToString() string {
-	elements := []string{}
-
-	sp.evaluateStack()
-
-	for i := 0; i < len(sp.segments); i++ {
-		// remove repeating commands names
-		cmd := sp.segments[i].Command
-		skipCmd := i > 0 && cmd != "m" && cmd != "M" && cmd == sp.segments[i-1].Command
-		if !skipCmd {
-			elements = append(elements, cmd)
-		}
-		for _, param := range sp.segments[i].Params {
-			elements = append(elements, fmt.Sprintf("%g", param))
-		}
-	}
-
-	// TODO: Optimizations: remove spaces around commands & before `-`
-	//
-	// We could also remove leading zeros for `0.5`-like values,
-	// but their count is too small to spend time for.
-	result := strings.Join(elements, " ")
-	result = regexp.MustCompile("(?i) ?([achlmqrstvz]) ?").ReplaceAllString(result, "$1")
-	result = regexp.MustCompile(" \\-").ReplaceAllString(result, "-")
-	return result
+	return sp.ToStringWithOptions(DefaultFormatOptions)
 }
 
 func toFixed(value float64, precision int) float64 {
@@ -237,7 +257,11 @@ func toFixed(value float64, precision int) float64 {
 // Round coords with given decimal precision.
 // 0 by default (to integers)
 //
-func (sp *SvgPath) Round(d int) {
+func (sp *SvgPath) Round(d int) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	contourStartDeltaX := 0.0
 	contourStartDeltaY := 0.0
 	deltaX := 0.0
@@ -300,6 +324,25 @@ func (sp *SvgPath) Round(d int) {
 			s.Params[5] = toFixed(s.Params[5], d)
 			s.Params[6] = toFixed(s.Params[6], d)
 
+		case "r":
+			// Catmull-Rom points are on the curve and all of them are
+			// relative to the segment start, so all get the correction
+			l = len(s.Params)
+
+			if isRelative {
+				for i := 0; i < l; i += 2 {
+					s.Params[i] = s.Params[i] + deltaX
+					s.Params[i+1] = s.Params[i+1] + deltaY
+				}
+			}
+
+			deltaX = s.Params[l-2] - toFixed(s.Params[l-2], d)
+			deltaY = s.Params[l-1] - toFixed(s.Params[l-1], d)
+
+			for i := 0; i < l; i++ {
+				s.Params[i] = toFixed(s.Params[i], d)
+			}
+
 		default:
 			// a c l q s t
 			l = len(s.Params)
@@ -317,6 +360,7 @@ func (sp *SvgPath) Round(d int) {
 			}
 		}
 	}
+	return sp
 }
 
 type IteratorFn func(segment *Segment, index int, lastX float64, lastY float64) []*Segment
@@ -417,65 +461,135 @@ func (sp *SvgPath) iterate(iterator IteratorFn, keepLazyStack bool) {
 
 // Translate path to (x  y)
 //
-func (sp *SvgPath) Translate(x, y float64) {
+func (sp *SvgPath) Translate(x, y float64) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	m := NewMatrix()
 	m.Translate(x, y)
 	sp.stack = append(sp.stack, m)
+	return sp
 }
 
 // Scale path to (sx , sy)
 //
-func (sp *SvgPath) Scale(sx, sy float64) {
+func (sp *SvgPath) Scale(sx, sy float64) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	m := NewMatrix()
 	m.Scale(sx, sy)
 	sp.stack = append(sp.stack, m)
+	return sp
 }
 
 // Rotate path around point (sx , sy)
 //
-func (sp *SvgPath) Rotate(angle, rx, ry float64) {
+func (sp *SvgPath) Rotate(angle, rx, ry float64) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	m := NewMatrix()
 	m.Rotate(angle, rx, ry)
 	sp.stack = append(sp.stack, m)
+	return sp
 }
 
 // Skew path along the X axis by `degrees` angle
 //
-func (sp *SvgPath) SkewX(degrees float64) {
+func (sp *SvgPath) SkewX(degrees float64) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	m := NewMatrix()
 	m.SkewX(degrees)
 	sp.stack = append(sp.stack, m)
+	return sp
 }
 
 // Skew path along the Y axis by `degrees` angle
 //
-func (sp *SvgPath) SkewY(degrees float64) {
+func (sp *SvgPath) SkewY(degrees float64) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	m := NewMatrix()
 	m.SkewY(degrees)
 	sp.stack = append(sp.stack, m)
+	return sp
 }
 
 // Apply matrix transform (array of 6 elements)
 //
-func (sp *SvgPath) Matrix(mv []float64) {
+func (sp *SvgPath) Matrix(mv []float64) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
+	if len(mv) != 6 {
+		sp.err = errors.Errorf("SvgPath: matrix should have 6 elements, got %d", len(mv))
+		return sp
+	}
+
 	m := NewMatrix()
 	m.Matrix(mv)
 	sp.stack = append(sp.stack, m)
+	return sp
 }
 
-// Transform path according to "transform" attr of SVG spec
+// Transform path according to "transform" attr of SVG spec. The
+// transform is applied as TransformParse reads it, skipping functions
+// it does not understand. Such string is also an error of the chain,
+// as reported by TransformParseStrict.
 //
-func (sp *SvgPath) Transform(transformString string) {
+func (sp *SvgPath) Transform(transformString string) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
+	if _, err := TransformParseStrict(transformString); err != nil {
+		sp.err = err
+	}
+
 	transformString = strings.Trim(transformString, " ")
 	if len(transformString) == 0 {
-		return
+		return sp
 	}
 	sp.stack = append(sp.stack, TransformParse(transformString))
+	return sp
+}
+
+// Transform path according to CSS `transform` property, around the
+// point given by CSS `transform-origin` (pass "" for the default 0 0).
+// See TransformParseCSS for the supported syntax. On error the path is
+// left unchanged and the error is kept for Err.
+//
+func (sp *SvgPath) TransformCSS(transform, origin string) *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
+	m, err := TransformParseCSS(transform, origin)
+	if err != nil {
+		sp.err = err
+		return sp
+	}
+	sp.stack = append(sp.stack, m)
+	return sp
 }
 
 // Converts *Segments from relative to absolute
 //
-func (sp *SvgPath) Abs() {
+func (sp *SvgPath) Abs() *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 
 	sp.iterate(func(s *Segment, index int, x float64, y float64) []*Segment {
 		name := s.Command
@@ -511,11 +625,16 @@ func (sp *SvgPath) Abs() {
 		}
 		return nil
 	}, true)
+	return sp
 }
 
 // Converts *Segments from absolute to relative
 //
-func (sp *SvgPath) Rel() {
+func (sp *SvgPath) Rel() *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 
 	sp.iterate(func(s *Segment, index int, x float64, y float64) []*Segment {
 		name := s.Command
@@ -556,11 +675,16 @@ func (sp *SvgPath) Rel() {
 		}
 		return nil
 	}, true)
+	return sp
 }
 
 // Converts arcs to cubic bézier curves
 //
-func (sp *SvgPath) Unarc() {
+func (sp *SvgPath) Unarc() *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	sp.iterate(func(s *Segment, index int, x float64, y float64) []*Segment {
 		result := []*Segment{}
 		name := s.Command
@@ -597,11 +721,65 @@ func (sp *SvgPath) Unarc() {
 
 		return result
 	}, false)
+	return sp
+}
+
+// Converts Catmull-Rom curves to cubic bézier curves
+//
+func (sp *SvgPath) Uncatmull() *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
+	sp.iterate(func(s *Segment, index int, x float64, y float64) []*Segment {
+		result := []*Segment{}
+		name := s.Command
+
+		// Skip anything except Catmull-Rom curves
+		if name != "R" && name != "r" {
+			return nil
+		}
+
+		// points of the curve, starting with the current one
+		points := make([]float64, 0, len(s.Params)+2)
+		points = append(points, x, y)
+		for i := 0; i < len(s.Params); i += 2 {
+			if name == "r" {
+				// convert relative coordinates to absolute
+				points = append(points, x+s.Params[i], y+s.Params[i+1])
+			} else {
+				points = append(points, s.Params[i], s.Params[i+1])
+			}
+		}
+
+		lastX := x
+		lastY := y
+		for _, c := range catmullRom2c(points) {
+			if name == "r" {
+				result = append(result, &Segment{
+					Command: "c",
+					Params: []float64{c[0] - lastX, c[1] - lastY,
+						c[2] - lastX, c[3] - lastY, c[4] - lastX, c[5] - lastY},
+				})
+				lastX = c[4]
+				lastY = c[5]
+			} else {
+				result = append(result, &Segment{Command: "C", Params: c})
+			}
+		}
+
+		return result
+	}, false)
+	return sp
 }
 
 // Converts smooth curves (with missed control point) to generic curves
 //
-func (sp *SvgPath) Unshort() {
+func (sp *SvgPath) Unshort() *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	segments := sp.segments
 	var prevControlX, prevControlY float64
 	var curControlX, curControlY float64
@@ -669,6 +847,12 @@ func (sp *SvgPath) Unshort() {
 			} else if prevSegment.Command == "c" {
 				prevControlX = prevSegment.Params[2] - prevSegment.Params[4]
 				prevControlY = prevSegment.Params[3] - prevSegment.Params[5]
+			} else if prevSegment.Command == "R" || prevSegment.Command == "r" {
+				// Catmull-Rom ends with a cubic, its last control point
+				// is 1/6 of the way to the previous point
+				l := len(prevSegment.Params)
+				prevControlX = (prevSegment.Params[l-4] - prevSegment.Params[l-2]) / 6
+				prevControlY = (prevSegment.Params[l-3] - prevSegment.Params[l-1]) / 6
 			} else {
 				prevControlX = 0.0
 				prevControlY = 0.0
@@ -698,12 +882,17 @@ func (sp *SvgPath) Unshort() {
 		}
 		return nil
 	}, false)
+	return sp
 }
 
 // Converts cubic bézier curves to quadratic bézier curves
 //  NOTE: does not process "short" cubic bézier curves
 //
-func (sp *SvgPath) Uncubic() {
+func (sp *SvgPath) Uncubic() *SvgPath {
+	if sp.err != nil {
+		return sp
+	}
+
 	sp.iterate(func(s *Segment, index int, x float64, y float64) []*Segment {
 		result := []*Segment{}
 		name := s.Command
@@ -767,4 +956,5 @@ func (sp *SvgPath) Uncubic() {
 
 		return result
 	}, false)
+	return sp
 }
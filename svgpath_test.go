@@ -681,3 +681,80 @@ func TestArcTransformEdgeCases(t *testing.T) {
 	   });
 	*/
 }
+
+func TestCatmullRom(t *testing.T) {
+	sp, err := NewSvgPath("M1 1 r1 1 2 2 R3 3 4 4 5 5 6 6")
+	assert.Nil(t, err)
+	sp.Abs()
+	assert.Equal(t, "M1 1R2 2 3 3R3 3 4 4 5 5 6 6", sp.ToString(), "should convert to absolute without merging curves")
+
+	sp, err = NewSvgPath("M1 1 r1 1 2 2 R3 3 4 4 5 5 6 6")
+	assert.Nil(t, err)
+	sp.Rel()
+	assert.Equal(t, "M1 1r1 1 2 2r0 0 1 1 2 2 3 3", sp.ToString(), "should convert to relative")
+
+	sp, err = NewSvgPath("M1 1 r1 1 2 2 R3 3 4 4")
+	assert.Nil(t, err)
+	sp.Matrix([]float64{2, 0, 0, 3, 10, 10})
+	assert.Equal(t, "M12 13r2 3 4 6R16 19 18 22", sp.ToString(), "should transform all points")
+
+	sp, err = NewSvgPath("M1.4 1.4 r1.4 1.4 2.4 2.4")
+	assert.Nil(t, err)
+	sp.Round(0)
+	assert.Equal(t, "M1 1r2 2 3 3", sp.ToString(), "should round all points")
+
+	sp, err = NewSvgPath("M0.4 0.4 r1.2 1.2 2.4 2.4 3.6 3.6")
+	assert.Nil(t, err)
+	sp.Round(0)
+	assert.Equal(t, "M0 0r2 2 3 3 4 4", sp.ToString(), "should correct all relative points")
+	sp.Abs()
+	assert.Equal(t, "M0 0R2 2 3 3 4 4", sp.ToString(), "rounded absolute points")
+}
+
+func TestUncatmull(t *testing.T) {
+	sp, err := NewSvgPath("M0 0 R10 0 20 10")
+	assert.Nil(t, err)
+	sp.Uncatmull()
+	sp.Round(2)
+	assert.Equal(t, "M0 0C1.67 0 6.67-1.67 10 0 13.33 1.67 18.33 8.33 20 10", sp.ToString(), "absolute curve")
+
+	sp, err = NewSvgPath("M0 0 r10 0 20 10")
+	assert.Nil(t, err)
+	sp.Uncatmull()
+	sp.Round(2)
+	assert.Equal(t, "M0 0c1.67 0 6.67-1.67 10 0 3.33 1.67 8.33 8.33 10 10", sp.ToString(), "relative curve")
+
+	sp, err = NewSvgPath("M0 0 R10 0 20 10 S 30 20 40 10")
+	assert.Nil(t, err)
+	sp.Unshort()
+	sp.Uncatmull()
+	sp.Round(2)
+	assert.Equal(t, "M0 0C1.67 0 6.67-1.67 10 0 13.33 1.67 18.33 8.33 20 10 21.67 11.67 30 20 40 10", sp.ToString(), "should reflect last control point")
+}
+
+func TestChain(t *testing.T) {
+	d, err := Parse("M10 10 L 20 20").Translate(10, 0).Scale(2, 2).Rel().Round(0).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, "M40 20l20 20", d)
+
+	sp := Parse("M10 10 L 20 20 G").Translate(10, 0).Abs()
+	assert.Equal(t, "SvgPath: bad command G (at pos 15)", sp.Err().Error())
+	d, err = sp.Result()
+	assert.Equal(t, "", d)
+	assert.Equal(t, sp.Err(), err)
+
+	sp = Parse("M10 10 L 20 20").Transform("rotate(x)").Scale(2, 2).Abs()
+	assert.Error(t, sp.Err(), "bad transform string")
+	d, err = sp.Result()
+	assert.Equal(t, "", d)
+	assert.Equal(t, sp.Err(), err)
+
+	// valid functions are still applied, as before
+	sp = Parse("M10 10 L 20 20").Transform("translate(10) rotate(x)")
+	assert.Error(t, sp.Err())
+	assert.Equal(t, "M20 10L30 20", sp.ToString())
+
+	sp = Parse("M10 10 L 20 20").Matrix([]float64{1, 2, 3}).Translate(10, 0)
+	assert.Equal(t, "SvgPath: matrix should have 6 elements, got 3", sp.Err().Error())
+	assert.Equal(t, "M10 10L20 20", sp.ToString(), "should stop at the failed step")
+}
@@ -2,8 +2,10 @@
 package svgpath
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var CMD_SPLIT_RE = regexp.MustCompile(`\s*(matrix|translate|scale|rotate|skewX|skewY)\s*\(\s*(.+?)\s*\)[\s,]*`)
@@ -95,3 +97,256 @@ func TransformParse(transformString string) *Matrix {
 	}
 	return matrix
 }
+
+// TransformError describes an invalid transform list
+type TransformError struct {
+	Offset int    // byte offset of the error
+	Func   string // transform function, empty if the name itself is bad
+	Arg    int    // 1-based index of the bad argument, 0 if not about an argument
+
+	msg string
+}
+
+func (e *TransformError) Error() string {
+	return "SvgPath: " + e.msg
+}
+
+// Allowed arguments count for each transform function
+var transformArity = map[string][]int{
+	"matrix":    {6},
+	"translate": {1, 2},
+	"scale":     {1, 2},
+	"rotate":    {1, 3},
+	"skewX":     {1},
+	"skewY":     {1},
+}
+
+type transformScanner struct {
+	str string
+	pos int
+
+	// numbers may be followed by units, so `e` without exponent
+	// digits ends the number instead of being an error
+	units bool
+}
+
+func (ts *transformScanner) fail(fn string, arg int, format string, args ...interface{}) error {
+	return &TransformError{
+		Offset: ts.pos,
+		Func:   fn,
+		Arg:    arg,
+		msg:    fmt.Sprintf(format, args...) + fmt.Sprintf(" (at pos %d)", ts.pos),
+	}
+}
+
+func (ts *transformScanner) more() bool {
+	return ts.pos < len(ts.str)
+}
+
+func (ts *transformScanner) skipSpaces() {
+	for ts.more() && isTransformSpace(ts.str[ts.pos]) {
+		ts.pos++
+	}
+}
+
+// comma-wsp, returns false if there was no separator at all
+//
+func (ts *transformScanner) skipCommaSpaces() bool {
+	start := ts.pos
+	ts.skipSpaces()
+	if ts.more() && ts.str[ts.pos] == ',' {
+		ts.pos++
+		ts.skipSpaces()
+	}
+	return ts.pos > start
+}
+
+func isTransformSpace(ch byte) bool {
+	return ch == 0x20 || ch == 0x09 || ch == 0x0A || ch == 0x0D || ch == 0x0C
+}
+
+func (ts *transformScanner) skipDigits() bool {
+	start := ts.pos
+	for ts.more() && isDigit(rune(ts.str[ts.pos])) {
+		ts.pos++
+	}
+	return ts.pos > start
+}
+
+// Scan SVG number: sign? (digits | digits? "." digits) exponent?
+//
+func (ts *transformScanner) scanNumber() (float64, bool) {
+	start := ts.pos
+	if ts.more() && (ts.str[ts.pos] == '+' || ts.str[ts.pos] == '-') {
+		ts.pos++
+	}
+	hasInt := ts.skipDigits()
+	hasFrac := false
+	if ts.more() && ts.str[ts.pos] == '.' {
+		ts.pos++
+		hasFrac = ts.skipDigits()
+	}
+	if !hasInt && !hasFrac {
+		ts.pos = start
+		return 0, false
+	}
+	if ts.more() && (ts.str[ts.pos] == 'e' || ts.str[ts.pos] == 'E') {
+		expStart := ts.pos
+		ts.pos++
+		if ts.more() && (ts.str[ts.pos] == '+' || ts.str[ts.pos] == '-') {
+			ts.pos++
+		}
+		if !ts.skipDigits() {
+			if !ts.units {
+				ts.pos = start
+				return 0, false
+			}
+			ts.pos = expStart
+		}
+	}
+	value, err := strconv.ParseFloat(ts.str[start:ts.pos], 64)
+	if err != nil {
+		ts.pos = start
+		return 0, false
+	}
+	return value, true
+}
+
+func isLetter(ch byte) bool {
+	return ch|0x20 >= 'a' && ch|0x20 <= 'z'
+}
+
+// Scan identifier: a letter followed by letters and digits
+//
+func (ts *transformScanner) scanName() string {
+	start := ts.pos
+	if ts.more() && isLetter(ts.str[ts.pos]) {
+		ts.pos++
+		for ts.more() && (isLetter(ts.str[ts.pos]) || isDigit(rune(ts.str[ts.pos]))) {
+			ts.pos++
+		}
+	}
+	return ts.str[start:ts.pos]
+}
+
+func (ts *transformScanner) checkArity(name string, arity []int, n int) error {
+	for _, count := range arity {
+		if count == n {
+			return nil
+		}
+	}
+	counts := make([]string, len(arity))
+	for i, count := range arity {
+		counts[i] = strconv.Itoa(count)
+	}
+	return ts.fail(name, 0, "%s takes %s arguments, got %d", name, strings.Join(counts, " or "), n)
+}
+
+// Scan `name(args)`, return the function name and its arguments
+//
+func (ts *transformScanner) scanTransform(params []float64) (string, []float64, error) {
+	namePos := ts.pos
+	name := ts.scanName()
+	if name == "" {
+		return "", nil, ts.fail("", 0, "expected transform function")
+	}
+	arity, ok := transformArity[name]
+	if !ok {
+		ts.pos = namePos
+		return "", nil, ts.fail("", 0, "unknown transform function `%s`", name)
+	}
+
+	ts.skipSpaces()
+	if !ts.more() || ts.str[ts.pos] != '(' {
+		return "", nil, ts.fail(name, 0, "expected `(` after %s", name)
+	}
+	ts.pos++
+	ts.skipSpaces()
+
+	separated := true
+	for ts.more() && ts.str[ts.pos] != ')' {
+		// separator is optional before sign or dot only
+		ch := ts.str[ts.pos]
+		if !separated && ch != '-' && ch != '+' && ch != '.' {
+			return "", nil, ts.fail(name, len(params)+1, "invalid argument %d of %s", len(params)+1, name)
+		}
+		value, ok := ts.scanNumber()
+		if !ok {
+			return "", nil, ts.fail(name, len(params)+1, "invalid argument %d of %s", len(params)+1, name)
+		}
+		params = append(params, value)
+
+		sepStart := ts.pos
+		separated = ts.skipCommaSpaces()
+		if ts.more() && ts.str[ts.pos] == ')' && strings.Contains(ts.str[sepStart:ts.pos], ",") {
+			return "", nil, ts.fail(name, len(params)+1, "invalid argument %d of %s", len(params)+1, name)
+		}
+	}
+
+	if !ts.more() {
+		return "", nil, ts.fail(name, 0, "expected `)` after arguments of %s", name)
+	}
+
+	if err := ts.checkArity(name, arity, len(params)); err != nil {
+		return "", nil, err
+	}
+	ts.pos++
+
+	return name, params, nil
+}
+
+// Strict version of TransformParse, implementing `transform` attribute
+// grammar of SVG spec. Unlike TransformParse, it fails on anything it
+// can't understand; error is *TransformError, which tells which function
+// and which argument are invalid.
+//
+func TransformParseStrict(transformString string) (*Matrix, error) {
+	matrix := NewMatrix()
+	ts := &transformScanner{str: transformString}
+	params := make([]float64, 0, 6)
+
+	ts.skipSpaces()
+	for ts.more() {
+		cmd, args, err := ts.scanTransform(params[:0])
+		if err != nil {
+			return nil, err
+		}
+		params = args
+
+		switch cmd {
+		case "matrix":
+			matrix.Matrix(append([]float64{}, params...))
+		case "scale":
+			if len(params) == 1 {
+				matrix.Scale(params[0], params[0])
+			} else {
+				matrix.Scale(params[0], params[1])
+			}
+		case "rotate":
+			if len(params) == 1 {
+				matrix.Rotate(params[0], 0, 0)
+			} else {
+				matrix.Rotate(params[0], params[1], params[2])
+			}
+		case "translate":
+			if len(params) == 1 {
+				matrix.Translate(params[0], 0)
+			} else {
+				matrix.Translate(params[0], params[1])
+			}
+		case "skewX":
+			matrix.SkewX(params[0])
+		case "skewY":
+			matrix.SkewY(params[0])
+		}
+
+		// comma is allowed between transforms only
+		sepStart := ts.pos
+		ts.skipCommaSpaces()
+		if !ts.more() && strings.Contains(ts.str[sepStart:], ",") {
+			return nil, ts.fail("", 0, "expected transform function")
+		}
+	}
+
+	return matrix, nil
+}
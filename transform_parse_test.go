@@ -4,6 +4,7 @@ package svgpath
 import (
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -171,3 +172,74 @@ func TestTransformMisc(t *testing.T) {
 	assert.Equal(t, "m170 170l70 70", sp.ToString(), "first m should be processed as absolute")
 
 }
+
+func TestTransformParseStrict(t *testing.T) {
+	m, err := TransformParseStrict("rotate(45,10,10)")
+	assert.Nil(t, err)
+	assert.Equal(t, TransformParse("rotate(45,10,10)").ToArray(), m.ToArray())
+
+	m, err = TransformParseStrict("  translate(1e1-2E+1)scale(.5.5),skewX(0)\n matrix(1 0 0 1 0 0)  ")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.5, 0, 0, 0.5, 10, -20}, m.ToArray())
+
+	m, err = TransformParseStrict("")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 0, 1, 0, 0}, m.ToArray())
+
+	sp, err := NewSvgPath("M10 10L15 10")
+	assert.Nil(t, err)
+	assert.Nil(t, sp.Transform("rotate(90 10 10)").Round(0).Err())
+	assert.Equal(t, "M10 10L10 15", sp.ToString())
+}
+
+func TestTransformParseStrictErrors(t *testing.T) {
+	var te *TransformError
+
+	_, err := TransformParseStrict("rotate(45,10)")
+	assert.Equal(t, "SvgPath: rotate takes 1 or 3 arguments, got 2 (at pos 12)", err.Error())
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, "rotate", te.Func)
+	assert.Equal(t, 0, te.Arg)
+
+	_, err = TransformParseStrict("translate(10) scale(1, x)")
+	assert.Equal(t, "SvgPath: invalid argument 2 of scale (at pos 23)", err.Error())
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, "scale", te.Func)
+	assert.Equal(t, 2, te.Arg)
+	assert.Equal(t, 23, te.Offset)
+
+	_, err = TransformParseStrict("scale(1e)")
+	assert.Equal(t, "SvgPath: invalid argument 1 of scale (at pos 6)", err.Error())
+
+	_, err = TransformParseStrict("shift(1)")
+	assert.Equal(t, "SvgPath: unknown transform function `shift` (at pos 0)", err.Error())
+
+	_, err = TransformParseStrict("scale 2")
+	assert.Equal(t, "SvgPath: expected `(` after scale (at pos 6)", err.Error())
+
+	_, err = TransformParseStrict("scale(2")
+	assert.Equal(t, "SvgPath: expected `)` after arguments of scale (at pos 7)", err.Error())
+
+	_, err = TransformParseStrict("scale(2),")
+	assert.Equal(t, "SvgPath: expected transform function (at pos 9)", err.Error())
+
+	_, err = TransformParseStrict("scale(2) ,, scale(2)")
+	assert.Equal(t, "SvgPath: expected transform function (at pos 10)", err.Error())
+
+	sp, err := NewSvgPath("M10 10L15 10")
+	assert.Nil(t, err)
+	sp.Transform("rotate(90 10)").Translate(10, 10)
+	assert.Equal(t, "SvgPath: rotate takes 1 or 3 arguments, got 2 (at pos 12)", sp.Err().Error())
+	assert.Equal(t, "M10 10L15 10", sp.ToString())
+}
+
+func TestTransformParseStrictSeparators(t *testing.T) {
+	_, err := TransformParseStrict("scale(1,)")
+	assert.Equal(t, "SvgPath: invalid argument 2 of scale (at pos 8)", err.Error())
+
+	_, err = TransformParseStrict("scale(1,,2)")
+	assert.Equal(t, "SvgPath: invalid argument 2 of scale (at pos 8)", err.Error())
+
+	_, err = TransformParseStrict("scale(1.5.5.5)")
+	assert.Equal(t, "SvgPath: scale takes 1 or 2 arguments, got 3 (at pos 13)", err.Error())
+}